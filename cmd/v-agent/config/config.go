@@ -6,6 +6,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -34,6 +36,8 @@ type Config struct {
 	Endpoint      string            `yaml:"endpoint"`
 	BasicAuthUser string            `yaml:"basic_auth_user"`
 	BasicAuthPass string            `yaml:"basic_auth_pass"`
+	CheckVendor   bool              `yaml:"check_vendor"`
+	VendorCheck   VendorCheck       `yaml:"vendor_check"`
 	LabelsConfig  map[string]string `yaml:"labels_config"`
 	ProbesAPI     ProbesAPI         `yaml:"probes_api"`
 	MetricsConfig MetricsConfig     `yaml:"metrics_config"`
@@ -41,6 +45,9 @@ type Config struct {
 	zapConfig *zap.Config
 	zapLogger *zap.Logger
 	zapSugar  *zap.SugaredLogger
+
+	vendor        string // bios vendor found by the vendor check
+	vendorMatched bool
 }
 
 // MetricsConfig contains metrics configuration
@@ -66,6 +73,26 @@ type AgentMetrics struct {
 	Ceph         Ceph         `yaml:"ceph"`
 	VDNS         VDNS         `yaml:"v_dns"`
 	SMART        SMART        `yaml:"smart"`
+	PSI          PSI          `yaml:"psi"`
+	VMStat       VMStat       `yaml:"vmstat"`
+	NetStat      NetStat      `yaml:"netstat"`
+	Conntrack    Conntrack    `yaml:"conntrack"`
+	Softnet      Softnet      `yaml:"softnet"`
+	TCPStates    TCPStates    `yaml:"tcp_states"`
+	Ethtool      Ethtool      `yaml:"ethtool"`
+	Hwmon        Hwmon        `yaml:"hwmon"`
+	CPUFreq      CPUFreq      `yaml:"cpufreq"`
+	MDAdm        MDAdm        `yaml:"mdadm"`
+	Processes    Processes    `yaml:"processes"`
+	Cgroups      Cgroups      `yaml:"cgroups"`
+	Systemd      Systemd      `yaml:"systemd"`
+	Timex        Timex        `yaml:"timex"`
+	NodeInfo     NodeInfo     `yaml:"node_info"`
+	KernelLimits KernelLimits `yaml:"kernel_limits"`
+	NUMA         NUMA         `yaml:"numa"`
+	Kmsg         Kmsg         `yaml:"kmsg"`
+	EDAC         EDAC         `yaml:"edac"`
+	NFS          NFS          `yaml:"nfs"`
 }
 
 // KubernetesMetrics metrics that are collected when ran as an operator (in k8s)
@@ -74,6 +101,13 @@ type KubernetesMetrics struct {
 	DCGM DCGM `yaml:"dcgm"`
 }
 
+// VendorCheck config, applied when check_vendor is true
+type VendorCheck struct {
+	Vendors              []string `yaml:"vendors"`
+	OnMismatch           string   `yaml:"on_mismatch"`
+	RestrictedCollectors []string `yaml:"restricted_collectors"`
+}
+
 // ProbesAPI probes API definition
 type ProbesAPI struct {
 	Listen string `yaml:"listen"`
@@ -88,6 +122,7 @@ type LoadAvg struct {
 // CPU configuration
 type CPU struct {
 	Enabled bool `yaml:"enabled"`
+	PerCPU  bool `yaml:"per_cpu"`
 }
 
 // Memory configuration
@@ -97,18 +132,31 @@ type Memory struct {
 
 // NIC configuration
 type NIC struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled       bool   `yaml:"enabled"`
+	Include       string `yaml:"include"`
+	Exclude       string `yaml:"exclude"`
+	PhysicalOnly  bool   `yaml:"physical_only"`
+	Aggregate     string `yaml:"aggregate"`
+	AggregateName string `yaml:"aggregate_name"`
 }
 
 // DiskStats configuration
 type DiskStats struct {
 	Enabled bool   `yaml:"enabled"`
-	Filter  string `yaml:"filter"`
+	Filter  string `yaml:"filter"` // deprecated, use exclude
+	Include string `yaml:"include"`
+	Exclude string `yaml:"exclude"`
+	ByID    bool   `yaml:"by_id"` // label series by /dev/disk/by-id name instead of kernel name
 }
 
 // Filesystem configuration
 type Filesystem struct {
-	Enabled bool `yaml:"enabled"`
+	Enabled       bool   `yaml:"enabled"`
+	FSTypeInclude string `yaml:"fstype_include"`
+	FSTypeExclude string `yaml:"fstype_exclude"`
+	MountInclude  string `yaml:"mount_include"`
+	MountExclude  string `yaml:"mount_exclude"`
+	StatfsTimeout uint   `yaml:"statfs_timeout"`
 }
 
 // Kubernetes config
@@ -176,6 +224,135 @@ type SMART struct {
 	BlockDevices []string `yaml:"block_devices"`
 }
 
+// PSI config
+type PSI struct {
+	Enabled bool     `yaml:"enabled"`
+	Cgroups []string `yaml:"cgroups"`
+}
+
+// VMStat config
+type VMStat struct {
+	Enabled bool   `yaml:"enabled"`
+	Fields  string `yaml:"fields"`
+}
+
+// NetStat config
+type NetStat struct {
+	Enabled bool   `yaml:"enabled"`
+	Fields  string `yaml:"fields"`
+}
+
+// Conntrack config
+type Conntrack struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Softnet config
+type Softnet struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// TCPStates config
+type TCPStates struct {
+	Enabled bool     `yaml:"enabled"`
+	Ports   []uint16 `yaml:"ports"`
+}
+
+// Ethtool config
+type Ethtool struct {
+	Enabled bool   `yaml:"enabled"`
+	Include string `yaml:"include"`
+	Exclude string `yaml:"exclude"`
+	Stats   string `yaml:"stats"`
+}
+
+// Hwmon config
+type Hwmon struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// CPUFreq config
+type CPUFreq struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// MDAdm config
+type MDAdm struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Processes config
+type Processes struct {
+	Enabled bool           `yaml:"enabled"`
+	Groups  []ProcessGroup `yaml:"groups"`
+}
+
+// ProcessGroup processes aggregated under a single name, a process must match every regex that is set
+type ProcessGroup struct {
+	Name    string `yaml:"name"`
+	Comm    string `yaml:"comm"`    // regex, matched against /proc/[pid]/comm
+	Cmdline string `yaml:"cmdline"` // regex, matched against /proc/[pid]/cmdline joined by spaces
+}
+
+// Cgroups config
+type Cgroups struct {
+	Enabled          bool     `yaml:"enabled"`
+	Root             string   `yaml:"root"` // cgroup v2 mount, also used by psi.cgroups
+	Paths            []string `yaml:"paths"`
+	MaxDepth         uint     `yaml:"max_depth"`
+	KubernetesLabels bool     `yaml:"kubernetes_labels"`
+}
+
+// Systemd config
+type Systemd struct {
+	Enabled bool     `yaml:"enabled"`
+	Units   []string `yaml:"units"`
+}
+
+// Timex config
+type Timex struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// NodeInfo config
+type NodeInfo struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// KernelLimits config
+type KernelLimits struct {
+	Enabled bool `yaml:"enabled"`
+	Inotify bool `yaml:"inotify"`
+}
+
+// NUMA config
+type NUMA struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Kmsg config
+type Kmsg struct {
+	Enabled   bool          `yaml:"enabled"`
+	Patterns  []KmsgPattern `yaml:"patterns"`
+	StateFile string        `yaml:"state_file"` // last read sequence number, to resume after a restart
+}
+
+// KmsgPattern a named regex matched against kernel log messages, the first non empty capture group is the match label
+type KmsgPattern struct {
+	Name  string `yaml:"name"`
+	Regex string `yaml:"regex"`
+}
+
+// EDAC config
+type EDAC struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// NFS config
+type NFS struct {
+	Enabled bool `yaml:"enabled"`
+}
+
 // Pods config
 type Pods struct {
 	Enabled    bool     `yaml:"enabled"`
@@ -220,6 +397,11 @@ func NewConfig(name, version string) (*Config, error) {
 		return nil, err
 	}
 
+	// Stage 7: Check vendor
+	if err := initVendorCheck(&cfg); err != nil {
+		return nil, err
+	}
+
 	cfg.Name = name
 	cfg.Version = version
 
@@ -429,6 +611,18 @@ func checkConfig(config *Config) error {
 		return fmt.Errorf("remote_write_endpoint: %w", ErrMissingScheme)
 	}
 
+	switch config.VendorCheck.OnMismatch {
+	case "", VendorCheckActionExit, VendorCheckActionIdle, VendorCheckActionRestricted:
+	default:
+		return fmt.Errorf("vendor_check.on_mismatch: %w: %s", ErrVendorCheckActionInvalid, config.VendorCheck.OnMismatch)
+	}
+
+	for _, c := range config.VendorCheck.RestrictedCollectors {
+		if !agentCollectorExists(c) {
+			return fmt.Errorf("vendor_check.restricted_collectors: %w: %s", ErrVendorCheckCollectorInvalid, c)
+		}
+	}
+
 	if config.MetricsConfig.Kubernetes.Pods.Enabled {
 		// try to get k8s connection, if error return
 		if !inK8s() {
@@ -455,6 +649,108 @@ func checkConfig(config *Config) error {
 		}
 	}
 
+	if config.MetricsConfig.Agent.DiskStats.Enabled {
+		for k, v := range map[string]string{
+			"disk_stats.filter":  config.MetricsConfig.Agent.DiskStats.Filter,
+			"disk_stats.include": config.MetricsConfig.Agent.DiskStats.Include,
+			"disk_stats.exclude": config.MetricsConfig.Agent.DiskStats.Exclude,
+		} {
+			if _, err := regexp.Compile(v); err != nil {
+				return fmt.Errorf("%s: %w: %s", k, ErrRegexInvalid, err)
+			}
+		}
+	}
+
+	if config.MetricsConfig.Agent.Filesystem.Enabled {
+		for k, v := range map[string]string{
+			"file_system.fstype_include": config.MetricsConfig.Agent.Filesystem.FSTypeInclude,
+			"file_system.fstype_exclude": config.MetricsConfig.Agent.Filesystem.FSTypeExclude,
+			"file_system.mount_include":  config.MetricsConfig.Agent.Filesystem.MountInclude,
+			"file_system.mount_exclude":  config.MetricsConfig.Agent.Filesystem.MountExclude,
+		} {
+			if _, err := regexp.Compile(v); err != nil {
+				return fmt.Errorf("%s: %w: %s", k, ErrRegexInvalid, err)
+			}
+		}
+	}
+
+	if config.MetricsConfig.Agent.NIC.Enabled {
+		for k, v := range map[string]string{
+			"nic.include":   config.MetricsConfig.Agent.NIC.Include,
+			"nic.exclude":   config.MetricsConfig.Agent.NIC.Exclude,
+			"nic.aggregate": config.MetricsConfig.Agent.NIC.Aggregate,
+		} {
+			if _, err := regexp.Compile(v); err != nil {
+				return fmt.Errorf("%s: %w: %s", k, ErrRegexInvalid, err)
+			}
+		}
+	}
+
+	if config.MetricsConfig.Agent.PSI.Enabled {
+		if _, err := os.Stat("/proc/pressure"); errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("psi: %w", ErrPSINotSupported)
+		}
+	}
+
+	if config.MetricsConfig.Agent.VMStat.Enabled {
+		if _, err := regexp.Compile(config.MetricsConfig.Agent.VMStat.Fields); err != nil {
+			return fmt.Errorf("vmstat.fields: %w: %s", ErrRegexInvalid, err)
+		}
+	}
+
+	if config.MetricsConfig.Agent.NetStat.Enabled {
+		if _, err := regexp.Compile(config.MetricsConfig.Agent.NetStat.Fields); err != nil {
+			return fmt.Errorf("netstat.fields: %w: %s", ErrRegexInvalid, err)
+		}
+	}
+
+	if config.MetricsConfig.Agent.Ethtool.Enabled {
+		for k, v := range map[string]string{
+			"ethtool.include": config.MetricsConfig.Agent.Ethtool.Include,
+			"ethtool.exclude": config.MetricsConfig.Agent.Ethtool.Exclude,
+			"ethtool.stats":   config.MetricsConfig.Agent.Ethtool.Stats,
+		} {
+			if _, err := regexp.Compile(v); err != nil {
+				return fmt.Errorf("%s: %w: %s", k, ErrRegexInvalid, err)
+			}
+		}
+	}
+
+	if config.MetricsConfig.Agent.Processes.Enabled {
+		for i := range config.MetricsConfig.Agent.Processes.Groups {
+			group := config.MetricsConfig.Agent.Processes.Groups[i]
+
+			if group.Name == "" || (group.Comm == "" && group.Cmdline == "") {
+				return fmt.Errorf("processes.groups[%d]: %w", i, ErrProcessGroupInvalid)
+			}
+
+			for k, v := range map[string]string{
+				"comm":    group.Comm,
+				"cmdline": group.Cmdline,
+			} {
+				if _, err := regexp.Compile(v); err != nil {
+					return fmt.Errorf("processes.groups[%d].%s: %w: %s", i, k, ErrRegexInvalid, err)
+				}
+			}
+		}
+	}
+
+	if config.MetricsConfig.Agent.Systemd.Enabled && len(config.MetricsConfig.Agent.Systemd.Units) == 0 {
+		return fmt.Errorf("systemd.units: %w", ErrSystemdUnitsNotSet)
+	}
+
+	if config.MetricsConfig.Agent.Kmsg.Enabled {
+		for i, p := range config.MetricsConfig.Agent.Kmsg.Patterns {
+			if p.Name == "" || p.Regex == "" {
+				return fmt.Errorf("kmsg.patterns[%d]: %w", i, ErrKmsgPatternInvalid)
+			}
+
+			if _, err := regexp.Compile(p.Regex); err != nil {
+				return fmt.Errorf("kmsg.patterns[%d].regex: %w: %s", i, ErrRegexInvalid, err)
+			}
+		}
+	}
+
 	if config.MetricsConfig.Kubernetes.DCGM.Enabled {
 		if !inK8s() {
 			return ErrNotInK8s
@@ -485,6 +781,98 @@ func checkConfig(config *Config) error {
 	return nil
 }
 
+// initVendorCheck compares the bios vendor against vendor_check.vendors when check_vendor is true
+//
+// on mismatch: exit returns ErrNotVultrVendor, idle stops metrics collection and restricted disables every collector not in vendor_check.restricted_collectors
+func initVendorCheck(config *Config) error {
+	log := zap.L().Sugar()
+
+	if !config.CheckVendor {
+		return nil
+	}
+
+	vendor, err := util.GetBIOSVendor()
+	if err != nil {
+		// no DMI (containers without /sys, some arm hosts), treated as a mismatch
+		log.Warnf("vendor check: unable to read bios vendor: %s", err)
+	} else {
+		config.vendor = *vendor
+	}
+
+	vendors := GetVendorCheckVendors()
+
+	config.vendorMatched = vendorMatches(config.vendor, vendors)
+	if config.vendorMatched {
+		log.Infof("vendor check: bios vendor %q matched", config.vendor)
+
+		return nil
+	}
+
+	switch GetVendorCheckOnMismatch() {
+	case VendorCheckActionIdle:
+		log.Warnf("vendor check: bios vendor %q not in %s, idling", config.vendor, strings.Join(vendors, ", "))
+	case VendorCheckActionRestricted:
+		log.Warnf("vendor check: bios vendor %q not in %s, only collecting %s", config.vendor, strings.Join(vendors, ", "), strings.Join(GetVendorCheckRestrictedCollectors(), ", "))
+
+		restrictCollectors(&config.MetricsConfig, GetVendorCheckRestrictedCollectors())
+	default:
+		return fmt.Errorf("%w: bios vendor %q not in %s", ErrNotVultrVendor, config.vendor, strings.Join(vendors, ", "))
+	}
+
+	return nil
+}
+
+// vendorMatches compares case insensitively, dmi strings are padded inconsistently across firmware
+func vendorMatches(vendor string, vendors []string) bool {
+	for _, v := range vendors {
+		if strings.EqualFold(strings.TrimSpace(vendor), strings.TrimSpace(v)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// restrictCollectors disables every metrics_config.agent collector not in allowed and every metrics_config.kubernetes collector
+func restrictCollectors(metricsConfig *MetricsConfig, allowed []string) {
+	allow := make(map[string]bool)
+	for _, a := range allowed {
+		allow[a] = true
+	}
+
+	agent := reflect.ValueOf(&metricsConfig.Agent).Elem()
+	for i := 0; i < agent.NumField(); i++ {
+		if allow[agent.Type().Field(i).Tag.Get("yaml")] {
+			continue
+		}
+
+		disableCollector(agent.Field(i))
+	}
+
+	kubernetes := reflect.ValueOf(&metricsConfig.Kubernetes).Elem()
+	for i := 0; i < kubernetes.NumField(); i++ {
+		disableCollector(kubernetes.Field(i))
+	}
+}
+
+func disableCollector(v reflect.Value) {
+	if enabled := v.FieldByName("Enabled"); enabled.IsValid() && enabled.Kind() == reflect.Bool {
+		enabled.SetBool(false)
+	}
+}
+
+// agentCollectorExists returns true if name is a metrics_config.agent key
+func agentCollectorExists(name string) bool {
+	t := reflect.TypeOf(AgentMetrics{})
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("yaml") == name {
+			return true
+		}
+	}
+
+	return false
+}
+
 func inK8s() bool {
 	v := os.Getenv("KUBERNETES_SERVICE_HOST")
 
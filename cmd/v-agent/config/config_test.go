@@ -0,0 +1,72 @@
+// Package config ensures the app is configured properly
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVendorMatches(t *testing.T) {
+	for _, tc := range []struct {
+		vendor   string
+		vendors  []string
+		expected bool
+	}{
+		{"Vultr", []string{"Vultr"}, true},
+		{"VULTR ", []string{"Vultr"}, true},
+		{"SeaBIOS", []string{"Vultr"}, false},
+		{"American Megatrends Inc.", []string{"Vultr", "American Megatrends Inc."}, true},
+		{"", []string{"Vultr"}, false},
+	} {
+		if got := vendorMatches(tc.vendor, tc.vendors); got != tc.expected {
+			t.Errorf("%q in %v: expected %t, got %t", tc.vendor, tc.vendors, tc.expected, got)
+		}
+	}
+}
+
+func TestRestrictCollectors(t *testing.T) {
+	var mc MetricsConfig
+
+	mc.Agent.LoadAvg.Enabled = true
+	mc.Agent.CPU.Enabled = true
+	mc.Agent.SMART.Enabled = true
+	mc.Agent.Processes.Enabled = true
+	mc.Kubernetes.Pods.Enabled = true
+
+	restrictCollectors(&mc, []string{"load_avg", "cpu"})
+
+	if !mc.Agent.LoadAvg.Enabled || !mc.Agent.CPU.Enabled {
+		t.Error("expected allowed collectors to stay enabled")
+	}
+
+	if mc.Agent.SMART.Enabled || mc.Agent.Processes.Enabled || mc.Kubernetes.Pods.Enabled {
+		t.Errorf("expected other collectors to be disabled %+v", mc)
+	}
+
+	// allowed collectors that were disabled stay disabled
+	if mc.Agent.Memory.Enabled {
+		t.Error("expected memory to stay disabled")
+	}
+}
+
+func TestCheckConfigVendorCheck(t *testing.T) {
+	for _, tc := range []struct {
+		vendorCheck VendorCheck
+		expected    error
+	}{
+		{VendorCheck{}, nil},
+		{VendorCheck{OnMismatch: VendorCheckActionRestricted, RestrictedCollectors: []string{"load_avg", "node_info"}}, nil},
+		{VendorCheck{OnMismatch: "ignore"}, ErrVendorCheckActionInvalid},
+		{VendorCheck{OnMismatch: VendorCheckActionRestricted, RestrictedCollectors: []string{"loadavg"}}, ErrVendorCheckCollectorInvalid},
+	} {
+		c := Config{
+			Interval:    60, //nolint
+			Endpoint:    "http://localhost:8080",
+			VendorCheck: tc.vendorCheck,
+		}
+
+		if err := checkConfig(&c); !errors.Is(err, tc.expected) {
+			t.Errorf("%+v: expected %v, got %v", tc.vendorCheck, tc.expected, err)
+		}
+	}
+}
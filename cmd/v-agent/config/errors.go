@@ -12,8 +12,12 @@ var (
 	ErrVPSIDNotSet = errors.New("vpsid is not set")
 	// ErrLabelNotExist returned when the specified label doesnt exist
 	ErrLabelNotExist = errors.New("label does not exist")
-	// ErrNotVultrVendor returned if the bios manufacturer is not "Vultr"
+	// ErrNotVultrVendor returned if the bios manufacturer is not in vendor_check.vendors ("Vultr" by default)
 	ErrNotVultrVendor = errors.New("not vultr vendor")
+	// ErrVendorCheckActionInvalid returned if vendor_check.on_mismatch is not exit, idle or restricted
+	ErrVendorCheckActionInvalid = errors.New("must be exit, idle or restricted")
+	// ErrVendorCheckCollectorInvalid returned if vendor_check.restricted_collectors names a collector that isn't under metrics_config.agent
+	ErrVendorCheckCollectorInvalid = errors.New("unknown metrics_config.agent collector")
 
 	ErrPortInvalid     = errors.New("port is invalid")
 	ErrIntervalInvalid = errors.New("interval is invalid")
@@ -27,6 +31,16 @@ var (
 
 	ErrSMARTDeviceNotExist = errors.New("smart.block_device does not exist")
 
+	ErrRegexInvalid = errors.New("invalid regex")
+
+	ErrPSINotSupported = errors.New("kernel does not support pressure stall information (/proc/pressure)")
+
+	ErrProcessGroupInvalid = errors.New("name and comm or cmdline must be set")
+
+	ErrSystemdUnitsNotSet = errors.New("no units set")
+
+	ErrKmsgPatternInvalid = errors.New("name and regex must be set")
+
 	ErrDCGMEndpointNotSet   = errors.New("dcgm.endpoint not set")
 	ErrDCGMEndpointNotExist = errors.New("dcgm.endpoint does not exist")
 )
@@ -7,6 +7,53 @@ import (
 	"go.uber.org/zap"
 )
 
+// DefaultVMStatFields /proc/vmstat fields collected when vmstat.fields is unset
+const DefaultVMStatFields = `^(pgfault|pgmajfault|pswpin|pswpout|pgpgin|pgpgout|oom_kill|pgscan_.*|pgsteal_.*|compact_.*|thp_.*)$`
+
+// DefaultNetStatFields /proc/net/{snmp,snmp6,netstat,sockstat,sockstat6} fields collected when netstat.fields is unset
+const DefaultNetStatFields = `^(sockets_used|TCP6?_(inuse|orphan|tw|alloc|mem)|UDP6?_(inuse|mem)|UDPLITE6?_inuse|RAW6?_inuse|FRAG6?_(inuse|memory)|Ip_Forwarding|Ip6?_(InReceives|InDelivers|OutRequests|InOctets|OutOctets)|Icmp6?_(InMsgs|OutMsgs|InErrors|OutErrors)|Tcp_(ActiveOpens|PassiveOpens|CurrEstab|InSegs|OutSegs|RetransSegs|InErrs|OutRsts|AttemptFails|EstabResets)|TcpExt_(ListenOverflows|ListenDrops|SyncookiesSent|SyncookiesRecv|SyncookiesFailed|TCPSynRetrans|TCPTimeouts|TCPBacklogDrop|TCPAbortOnMemory|TCPAbortOnTimeout|PruneCalled|TW)|Udp6?_(InDatagrams|OutDatagrams|NoPorts|InErrors|RcvbufErrors|SndbufErrors)|UdpLite6?_InErrors)$`
+
+// DefaultFilesystemFSTypeExclude pseudo and virtual filesystem types skipped when file_system.fstype_exclude is unset
+const DefaultFilesystemFSTypeExclude = `^(autofs|binfmt_misc|bpf|cgroup2?|configfs|debugfs|devpts|devtmpfs|efivarfs|fusectl|fuse\.lxcfs|hugetlbfs|iso9660|mqueue|nsfs|overlay|proc|pstore|ramfs|rpc_pipefs|securityfs|selinuxfs|squashfs|sysfs|tmpfs|tracefs)$`
+
+// DefaultFilesystemMountExclude mount points skipped when file_system.mount_exclude is unset
+const DefaultFilesystemMountExclude = `^/(dev|proc|sys|run/containerd/.+|run/k3s/.+|var/lib/(docker|containerd|kubelet)/.+)($|/)`
+
+// DefaultFilesystemStatfsTimeout seconds to wait on statfs when file_system.statfs_timeout is unset
+const DefaultFilesystemStatfsTimeout = 5
+
+// DefaultCgroupRoot cgroup v2 mount used when cgroups.root is unset
+const DefaultCgroupRoot = "/sys/fs/cgroup"
+
+// DefaultCgroupMaxDepth cgroups below cgroups.paths collected when cgroups.max_depth is unset, enough to reach containers under kubepods.slice
+const DefaultCgroupMaxDepth = 3
+
+// DefaultVendorCheckVendor bios vendor accepted when vendor_check.vendors is unset
+const DefaultVendorCheckVendor = "Vultr"
+
+// vendor_check.on_mismatch actions
+const (
+	VendorCheckActionExit       = "exit"       // exit with ErrNotVultrVendor, the default
+	VendorCheckActionIdle       = "idle"       // keep the probes api up, only push v_agent_version and v_vendor_check
+	VendorCheckActionRestricted = "restricted" // only collect vendor_check.restricted_collectors
+)
+
+// DefaultVendorCheckRestrictedCollectors collectors kept when vendor_check.restricted_collectors is unset
+var DefaultVendorCheckRestrictedCollectors = []string{"load_avg", "cpu", "memory", "node_info"}
+
+// DefaultKmsgStateFile file the last read /dev/kmsg sequence number is kept in when kmsg.state_file is unset
+const DefaultKmsgStateFile = "/var/lib/v-agent/kmsg.state"
+
+// DefaultKmsgPatterns kernel log patterns used when kmsg.patterns is unset
+var DefaultKmsgPatterns = []KmsgPattern{
+	{Name: "oom_kill", Regex: `Killed process [0-9]+ \(([^)]+)\)`},
+	{Name: "hung_task", Regex: `task (.+):[0-9]+ blocked for more than [0-9]+ seconds`},
+	{Name: "fs_error", Regex: `EXT4-fs error \(device ([^)]+)\)|XFS \(([^)]+)\): .*(?:[Cc]orruption|I/O error|Filesystem has been shut down)`},
+	{Name: "io_error", Regex: `I/O error, dev ([^,]+),`},
+	{Name: "link_down", Regex: `([^\s:]+):? (?:NIC )?[Ll]ink (?:is )?[Dd]own`},
+	{Name: "mce", Regex: `mce: \[Hardware Error\]|[Mm]achine check events logged`},
+}
+
 // GetConfig returns config
 func GetConfig() *Config {
 	return &cfg
@@ -57,13 +104,94 @@ func GetProbesAPIPort() uint {
 	return cfg.ProbesAPI.Port
 }
 
+// VendorCheckEnabled returns true if check_vendor is enabled
+func VendorCheckEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.CheckVendor
+}
+
+// GetVendorCheckVendors returns the accepted bios vendors, DefaultVendorCheckVendor if unset
+func GetVendorCheckVendors() []string {
+	cfg := GetConfig()
+
+	if len(cfg.VendorCheck.Vendors) == 0 {
+		return []string{DefaultVendorCheckVendor}
+	}
+
+	return cfg.VendorCheck.Vendors
+}
+
+// GetVendorCheckOnMismatch returns the vendor_check.on_mismatch action, VendorCheckActionExit if unset
+func GetVendorCheckOnMismatch() string {
+	cfg := GetConfig()
+
+	if cfg.VendorCheck.OnMismatch == "" {
+		return VendorCheckActionExit
+	}
+
+	return cfg.VendorCheck.OnMismatch
+}
+
+// GetVendorCheckRestrictedCollectors returns the collectors kept on mismatch, DefaultVendorCheckRestrictedCollectors if unset
+func GetVendorCheckRestrictedCollectors() []string {
+	cfg := GetConfig()
+
+	if len(cfg.VendorCheck.RestrictedCollectors) == 0 {
+		return DefaultVendorCheckRestrictedCollectors
+	}
+
+	return cfg.VendorCheck.RestrictedCollectors
+}
+
+// GetVendorCheckResult returns the bios vendor and true if it matched vendor_check.vendors
+func GetVendorCheckResult() (string, bool) {
+	cfg := GetConfig()
+
+	return cfg.vendor, cfg.vendorMatched
+}
+
+// VendorCheckIdle returns true if the vendor check failed and on_mismatch is idle, only metadata metrics are collected
+func VendorCheckIdle() bool {
+	cfg := GetConfig()
+
+	return cfg.CheckVendor && !cfg.vendorMatched && GetVendorCheckOnMismatch() == VendorCheckActionIdle
+}
+
 // GetDiskStatsFilter returns the regex for the disk stats filter
+//
+// Deprecated: use GetDiskStatsExclude
 func GetDiskStatsFilter() string {
 	cfg := GetConfig()
 
 	return cfg.MetricsConfig.Agent.DiskStats.Filter
 }
 
+// GetDiskStatsInclude returns the regex of devices to collect disk stats for, empty includes all
+func GetDiskStatsInclude() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.DiskStats.Include
+}
+
+// GetDiskStatsExclude returns the regex of devices to skip, falls back to disk_stats.filter
+func GetDiskStatsExclude() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.DiskStats.Exclude == "" {
+		return cfg.MetricsConfig.Agent.DiskStats.Filter
+	}
+
+	return cfg.MetricsConfig.Agent.DiskStats.Exclude
+}
+
+// GetDiskStatsByID returns true if disk series are labelled by id (/dev/disk/by-id name) instead of device
+func GetDiskStatsByID() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.DiskStats.ByID
+}
+
 // GetKubeconfig returns path to kubeconfig
 func GetKubeconfig() string {
 	cfg := GetConfig()
@@ -127,6 +255,13 @@ func CPUMetricCollectionEnabled() bool {
 	return cfg.MetricsConfig.Agent.CPU.Enabled
 }
 
+// CPUPerCPUMetricCollectionEnabled returns true/false if per cpu (core) metrics collection enabled
+func CPUPerCPUMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.CPU.PerCPU
+}
+
 // MemoryMetricCollectionEnabled returns true/false if memory metrics collection enabled
 func MemoryMetricCollectionEnabled() bool {
 	cfg := GetConfig()
@@ -141,6 +276,45 @@ func NICMetricCollectionEnabled() bool {
 	return cfg.MetricsConfig.Agent.NIC.Enabled
 }
 
+// GetNICInclude returns the regex of interfaces to collect nic metrics for, empty includes all
+func GetNICInclude() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NIC.Include
+}
+
+// GetNICExclude returns the regex of interfaces to skip, empty excludes none
+func GetNICExclude() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NIC.Exclude
+}
+
+// NICPhysicalOnly returns true if interfaces without a backing device should be skipped
+func NICPhysicalOnly() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NIC.PhysicalOnly
+}
+
+// GetNICAggregate returns the regex of interfaces to roll up into a single series, empty disables
+func GetNICAggregate() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NIC.Aggregate
+}
+
+// GetNICAggregateName returns the nic label used for the rolled up series
+func GetNICAggregateName() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.NIC.AggregateName == "" {
+		return "virtual"
+	}
+
+	return cfg.MetricsConfig.Agent.NIC.AggregateName
+}
+
 // DiskStatsMetricCollectionEnabled returns true/false if diskstats collection enabled
 func DiskStatsMetricCollectionEnabled() bool {
 	cfg := GetConfig()
@@ -155,6 +329,53 @@ func FileSystemMetricCollectionEnabled() bool {
 	return cfg.MetricsConfig.Agent.Filesystem.Enabled
 }
 
+// GetFilesystemFSTypeInclude returns the regex of filesystem types to collect, empty includes all
+func GetFilesystemFSTypeInclude() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Filesystem.FSTypeInclude
+}
+
+// GetFilesystemFSTypeExclude returns the regex of filesystem types to skip
+func GetFilesystemFSTypeExclude() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.Filesystem.FSTypeExclude == "" {
+		return DefaultFilesystemFSTypeExclude
+	}
+
+	return cfg.MetricsConfig.Agent.Filesystem.FSTypeExclude
+}
+
+// GetFilesystemMountInclude returns the regex of mount points to collect, empty includes all
+func GetFilesystemMountInclude() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Filesystem.MountInclude
+}
+
+// GetFilesystemMountExclude returns the regex of mount points to skip
+func GetFilesystemMountExclude() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.Filesystem.MountExclude == "" {
+		return DefaultFilesystemMountExclude
+	}
+
+	return cfg.MetricsConfig.Agent.Filesystem.MountExclude
+}
+
+// GetFilesystemStatfsTimeout returns the seconds to wait on statfs before reporting a device error
+func GetFilesystemStatfsTimeout() uint {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.Filesystem.StatfsTimeout == 0 {
+		return DefaultFilesystemStatfsTimeout
+	}
+
+	return cfg.MetricsConfig.Agent.Filesystem.StatfsTimeout
+}
+
 // KubernetesMetricCollectionEnabled returns true/false if Kubernetes collection enabled
 func KubernetesMetricCollectionEnabled() bool {
 	cfg := GetConfig()
@@ -289,6 +510,286 @@ func GetSMARTBlockDevices() []string {
 	return blockDevices
 }
 
+// PSIMetricCollectionEnabled returns true/false if psi collection enabled
+func PSIMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.PSI.Enabled
+}
+
+// GetPSICgroups returns cgroup v2 paths to collect psi for
+func GetPSICgroups() []string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.PSI.Cgroups
+}
+
+// VMStatMetricCollectionEnabled returns true/false if vmstat collection enabled
+func VMStatMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.VMStat.Enabled
+}
+
+// GetVMStatFields returns the regex of /proc/vmstat fields to collect
+func GetVMStatFields() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.VMStat.Fields == "" {
+		return DefaultVMStatFields
+	}
+
+	return cfg.MetricsConfig.Agent.VMStat.Fields
+}
+
+// NetStatMetricCollectionEnabled returns true/false if netstat (snmp, netstat, sockstat) collection enabled
+func NetStatMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NetStat.Enabled
+}
+
+// GetNetStatFields returns the regex of /proc/net/{snmp,snmp6,netstat,sockstat,sockstat6} fields to collect
+func GetNetStatFields() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.NetStat.Fields == "" {
+		return DefaultNetStatFields
+	}
+
+	return cfg.MetricsConfig.Agent.NetStat.Fields
+}
+
+// ConntrackMetricCollectionEnabled returns true/false if conntrack collection enabled
+func ConntrackMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Conntrack.Enabled
+}
+
+// SoftnetMetricCollectionEnabled returns true/false if softnet collection enabled
+func SoftnetMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Softnet.Enabled
+}
+
+// TCPStatesMetricCollectionEnabled returns true/false if tcp states collection enabled
+func TCPStatesMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.TCPStates.Enabled
+}
+
+// GetTCPStatesPorts returns local ports to break tcp states down by
+func GetTCPStatesPorts() []uint16 {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.TCPStates.Ports
+}
+
+// EthtoolMetricCollectionEnabled returns true/false if ethtool collection enabled
+func EthtoolMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Ethtool.Enabled
+}
+
+// GetEthtoolInclude returns the regex of interfaces to collect ethtool metrics for, empty includes all
+func GetEthtoolInclude() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Ethtool.Include
+}
+
+// GetEthtoolExclude returns the regex of interfaces to skip, empty excludes none
+func GetEthtoolExclude() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Ethtool.Exclude
+}
+
+// GetEthtoolStatsFilter returns the regex of driver statistics to collect, empty includes all
+func GetEthtoolStatsFilter() string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Ethtool.Stats
+}
+
+// HwmonMetricCollectionEnabled returns true/false if hwmon and thermal zone collection enabled
+func HwmonMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Hwmon.Enabled
+}
+
+// CPUFreqMetricCollectionEnabled returns true/false if cpu frequency and throttling collection enabled
+func CPUFreqMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.CPUFreq.Enabled
+}
+
+// MDAdmMetricCollectionEnabled returns true/false if software raid (md) collection enabled
+func MDAdmMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.MDAdm.Enabled
+}
+
+// ProcessesMetricCollectionEnabled returns true/false if process group collection enabled
+func ProcessesMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Processes.Enabled
+}
+
+// GetProcessGroups returns the process groups to collect
+func GetProcessGroups() []ProcessGroup {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Processes.Groups
+}
+
+// CgroupsMetricCollectionEnabled returns true/false if cgroup v2 collection enabled
+func CgroupsMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Cgroups.Enabled
+}
+
+// GetCgroupRoot returns the cgroup v2 mount, the host cgroupfs mounted into the pod when running as a daemonset
+func GetCgroupRoot() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.Cgroups.Root == "" {
+		return DefaultCgroupRoot
+	}
+
+	return cfg.MetricsConfig.Agent.Cgroups.Root
+}
+
+// GetCgroupPaths returns the cgroup subtrees to walk, relative to cgroups.root
+func GetCgroupPaths() []string {
+	cfg := GetConfig()
+
+	if len(cfg.MetricsConfig.Agent.Cgroups.Paths) == 0 {
+		return []string{"system.slice", "kubepods.slice"}
+	}
+
+	return cfg.MetricsConfig.Agent.Cgroups.Paths
+}
+
+// GetCgroupMaxDepth returns how many levels below each path are collected
+func GetCgroupMaxDepth() uint {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.Cgroups.MaxDepth == 0 {
+		return DefaultCgroupMaxDepth
+	}
+
+	return cfg.MetricsConfig.Agent.Cgroups.MaxDepth
+}
+
+// CgroupKubernetesLabelsEnabled returns true if pod_uid and container_id should be extracted from cgroup paths
+func CgroupKubernetesLabelsEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Cgroups.KubernetesLabels
+}
+
+// SystemdMetricCollectionEnabled returns true/false if systemd unit collection enabled
+func SystemdMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Systemd.Enabled
+}
+
+// GetSystemdUnits returns the systemd units to collect
+func GetSystemdUnits() []string {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Systemd.Units
+}
+
+// TimexMetricCollectionEnabled returns true/false if time synchronization (adjtimex) collection enabled
+func TimexMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Timex.Enabled
+}
+
+// NodeInfoMetricCollectionEnabled returns true/false if node identity (uname, os-release, dmi) collection enabled
+func NodeInfoMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NodeInfo.Enabled
+}
+
+// KernelLimitsMetricCollectionEnabled returns true/false if fd, inode, entropy, pid and inotify limit collection enabled
+func KernelLimitsMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.KernelLimits.Enabled
+}
+
+// KernelLimitsInotifyEnabled returns true/false if per user inotify usage is collected, walks every fd of every process
+func KernelLimitsInotifyEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.KernelLimits.Inotify
+}
+
+// NUMAMetricCollectionEnabled returns true/false if numa node memory, numastat and hugepage collection enabled
+func NUMAMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NUMA.Enabled
+}
+
+// KmsgMetricCollectionEnabled returns true/false if kernel log (/dev/kmsg) pattern collection enabled
+func KmsgMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.Kmsg.Enabled
+}
+
+// GetKmsgPatterns returns the kernel log patterns, DefaultKmsgPatterns if unset
+func GetKmsgPatterns() []KmsgPattern {
+	cfg := GetConfig()
+
+	if len(cfg.MetricsConfig.Agent.Kmsg.Patterns) == 0 {
+		return DefaultKmsgPatterns
+	}
+
+	return cfg.MetricsConfig.Agent.Kmsg.Patterns
+}
+
+// GetKmsgStateFile returns the file the last read sequence number is kept in, DefaultKmsgStateFile if unset
+func GetKmsgStateFile() string {
+	cfg := GetConfig()
+
+	if cfg.MetricsConfig.Agent.Kmsg.StateFile == "" {
+		return DefaultKmsgStateFile
+	}
+
+	return cfg.MetricsConfig.Agent.Kmsg.StateFile
+}
+
+// EDACMetricCollectionEnabled returns true/false if EDAC memory error collection enabled
+func EDACMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.EDAC.Enabled
+}
+
+// NFSMetricCollectionEnabled returns true/false if NFS client collection enabled
+func NFSMetricCollectionEnabled() bool {
+	cfg := GetConfig()
+
+	return cfg.MetricsConfig.Agent.NFS.Enabled
+}
+
 // DCGMCollectionEnabled returns true if DCGM collection is enabled
 func DCGMCollectionEnabled() bool {
 	cfg := GetConfig()
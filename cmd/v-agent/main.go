@@ -99,6 +99,15 @@ func main() {
 
 		metrics.NewMetrics()
 
+		// vendor_check.on_mismatch: idle, keep the probes api up and only push v_agent_version/v_vendor_check so the
+		// mismatch is visible
+		gather := metrics.Gather
+		if config.VendorCheckIdle() {
+			log.Warn("metrics worker: vendor check failed, only gathering metadata metrics")
+
+			gather = metrics.GatherMetadata
+		}
+
 		for {
 			counter++
 
@@ -114,7 +123,7 @@ func main() {
 
 					start := time.Now()
 
-					if err := metrics.Gather(); err != nil {
+					if err := gather(); err != nil {
 						log.Warn(err)
 					}
 
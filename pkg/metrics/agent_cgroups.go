@@ -0,0 +1,306 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+
+	"go.uber.org/zap"
+)
+
+const sysDevBlockPath = "/sys/dev/block"
+
+var (
+	// kubepods-burstable-pod<uid>.slice (systemd driver, dashes in the uid are underscores) or pod<uid> (cgroupfs driver)
+	cgroupPodUIDRe = regexp.MustCompile(`pod([0-9a-f]{8}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{4}[-_][0-9a-f]{12})(\.slice)?$`)
+
+	// cri-containerd-<id>.scope, crio-<id>.scope, docker-<id>.scope (systemd driver) or <id> (cgroupfs driver)
+	cgroupContainerIDRe = regexp.MustCompile(`^(?:[a-z-]+-)?([0-9a-f]{64})(\.scope)?$`)
+)
+
+// CgroupStats resource usage of a cgroup v2 cgroup
+//
+// https://docs.kernel.org/admin-guide/cgroup-v2.html
+type CgroupStats struct {
+	Cgroup      string // relative to cgroups.root
+	PodUID      string
+	ContainerID string
+
+	CPU          map[string]uint64 // cpu.stat, usec values and period counts
+	MemoryEvents map[string]uint64 // memory.events
+	IO           []*CgroupIOStats
+
+	MemoryCurrent uint64
+	MemoryMax     int64 // -1 if unlimited or the memory controller isn't enabled
+	PidsCurrent   uint64
+	PidsMax       int64 // -1 if unlimited or the pids controller isn't enabled
+}
+
+// CgroupIOStats a single device line from io.stat
+type CgroupIOStats struct {
+	Device string // device name, major:minor if it can't be resolved
+	Fields map[string]uint64
+}
+
+// getCgroupStats walks the configured cgroups.paths subtrees down to cgroups.max_depth
+func getCgroupStats() ([]*CgroupStats, error) {
+	root := config.GetCgroupRoot()
+
+	if _, err := os.Stat(filepath.Join(root, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("cgroups: %s is not cgroup v2: %w", root, err)
+	}
+
+	return walkCgroups(root, config.GetCgroupPaths(), config.GetCgroupMaxDepth(), config.CgroupKubernetesLabelsEnabled())
+}
+
+func walkCgroups(root string, paths []string, maxDepth uint, kubernetesLabels bool) ([]*CgroupStats, error) {
+	log := zap.L().Sugar()
+
+	devices := make(map[string]string)
+
+	var stats []*CgroupStats
+
+	for _, p := range paths {
+		base := filepath.Join(root, p)
+
+		err := filepath.WalkDir(base, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				// cgroups are removed while walking
+				if errors.Is(err, os.ErrNotExist) && path != base {
+					return nil
+				}
+
+				return err
+			}
+
+			if !d.IsDir() {
+				return nil
+			}
+
+			fromBase, err := filepath.Rel(base, path)
+			if err != nil {
+				return err
+			}
+
+			if fromBase != "." && uint(strings.Count(fromBase, "/")+1) > maxDepth {
+				return filepath.SkipDir
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			if rel == "." {
+				rel = "/"
+			}
+
+			cs, err := readCgroup(path, rel, devices)
+			if err != nil {
+				log.Debugf("cgroups: %s: %s", rel, err)
+
+				return nil
+			}
+
+			if kubernetesLabels {
+				cs.PodUID, cs.ContainerID = kubernetesIDsFromCgroup(rel)
+			}
+
+			stats = append(stats, cs)
+
+			return nil
+		})
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				log.Warnf("cgroups: %s does not exist, skipping", base)
+
+				continue
+			}
+
+			return nil, err
+		}
+	}
+
+	return stats, nil
+}
+
+// readCgroup reads the controller files of a single cgroup, files of controllers that aren't enabled are skipped
+func readCgroup(dir, rel string, devices map[string]string) (*CgroupStats, error) {
+	cs := CgroupStats{
+		Cgroup:    rel,
+		MemoryMax: -1,
+		PidsMax:   -1,
+	}
+
+	var err error
+
+	// cpu.stat is always present, usage_usec/user_usec/system_usec exist without the cpu controller
+	if cs.CPU, err = readFlatKeyed(filepath.Join(dir, "cpu.stat")); err != nil {
+		return nil, err
+	}
+
+	if cs.MemoryEvents, err = readFlatKeyed(filepath.Join(dir, "memory.events")); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	if v, err := readSysFloat(filepath.Join(dir, "memory.current")); err == nil {
+		cs.MemoryCurrent = uint64(v)
+	}
+
+	if v, err := readSysFloat(filepath.Join(dir, "memory.max")); err == nil {
+		cs.MemoryMax = int64(v)
+	}
+
+	if v, err := readSysFloat(filepath.Join(dir, "pids.current")); err == nil {
+		cs.PidsCurrent = uint64(v)
+	}
+
+	if v, err := readSysFloat(filepath.Join(dir, "pids.max")); err == nil {
+		cs.PidsMax = int64(v)
+	}
+
+	if fd, err := os.Open(filepath.Join(dir, "io.stat")); err == nil { //nolint
+		cs.IO, err = parseCgroupIOStat(fd)
+		fd.Close() //nolint
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range cs.IO {
+			cs.IO[i].Device = blockDeviceName(cs.IO[i].Device, devices)
+		}
+	}
+
+	return &cs, nil
+}
+
+// readFlatKeyed reads a flat keyed cgroup file (cpu.stat, memory.events):
+//
+//	usage_usec 2283049
+//	user_usec 1360431
+func readFlatKeyed(path string) (map[string]uint64, error) {
+	fd, err := os.Open(path) //nolint
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close() //nolint
+
+	return parseFlatKeyed(fd)
+}
+
+func parseFlatKeyed(r io.Reader) (map[string]uint64, error) {
+	sc := bufio.NewScanner(r)
+
+	stats := make(map[string]uint64)
+
+	for sc.Scan() {
+		splitted := strings.Fields(sc.Text())
+		if len(splitted) != 2 { //nolint
+			continue
+		}
+
+		v, err := strconv.ParseUint(splitted[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		stats[splitted[0]] = v
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// parseCgroupIOStat parses io.stat:
+//
+//	8:0 rbytes=90430464 wbytes=299008000 rios=8950 wios=12252 dbytes=0 dios=0
+func parseCgroupIOStat(r io.Reader) ([]*CgroupIOStats, error) {
+	sc := bufio.NewScanner(r)
+
+	var stats []*CgroupIOStats
+
+	for sc.Scan() {
+		splitted := strings.Fields(sc.Text())
+		if len(splitted) < 2 { //nolint
+			continue
+		}
+
+		ios := CgroupIOStats{
+			Device: splitted[0],
+			Fields: make(map[string]uint64),
+		}
+
+		for _, kv := range splitted[1:] {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("malformed field %q in io.stat", kv)
+			}
+
+			val, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			ios.Fields[k] = val
+		}
+
+		stats = append(stats, &ios)
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// blockDeviceName resolves major:minor to a device name using /sys/dev/block, cached in devices
+func blockDeviceName(majorMinor string, devices map[string]string) string {
+	if name, ok := devices[majorMinor]; ok {
+		return name
+	}
+
+	name := majorMinor
+	if target, err := os.Readlink(filepath.Join(sysDevBlockPath, majorMinor)); err == nil {
+		name = filepath.Base(target)
+	}
+
+	devices[majorMinor] = name
+
+	return name
+}
+
+// kubernetesIDsFromCgroup extracts the pod uid and container id from a kubepods cgroup path:
+//
+//	kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod<uid>.slice/cri-containerd-<id>.scope
+//	kubepods/burstable/pod<uid>/<id>
+func kubernetesIDsFromCgroup(cgroup string) (string, string) {
+	var podUID, containerID string
+
+	for _, part := range strings.Split(cgroup, "/") {
+		if m := cgroupPodUIDRe.FindStringSubmatch(part); m != nil {
+			podUID = strings.ReplaceAll(m[1], "_", "-")
+
+			continue
+		}
+
+		if m := cgroupContainerIDRe.FindStringSubmatch(part); m != nil && podUID != "" {
+			containerID = m[1]
+		}
+	}
+
+	return podUID, containerID
+}
@@ -0,0 +1,99 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCgroupIOStat(t *testing.T) {
+	stats, err := parseCgroupIOStat(strings.NewReader("8:0 rbytes=90430464 wbytes=299008000 rios=8950 wios=12252 dbytes=0 dios=0\n253:1 rbytes=4096 wbytes=0 rios=1 wios=0 dbytes=0 dios=0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 2 || stats[0].Device != "8:0" || stats[0].Fields["wbytes"] != 299008000 || stats[1].Fields["rios"] != 1 {
+		t.Errorf("unexpected io.stat %+v", stats)
+	}
+
+	if _, err := parseCgroupIOStat(strings.NewReader("8:0 rbytes\n")); err == nil {
+		t.Error("expected error for malformed io.stat")
+	}
+}
+
+func TestKubernetesIDsFromCgroup(t *testing.T) {
+	id := strings.Repeat("0123456789abcdef", 4)
+
+	for cgroup, expected := range map[string][2]string{
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1d3b6f2e_5c4a_4b7e_9d1f_2a3b4c5d6e7f.slice/cri-containerd-" + id + ".scope": {"1d3b6f2e-5c4a-4b7e-9d1f-2a3b4c5d6e7f", id},
+		"kubepods.slice/kubepods-pod1d3b6f2e_5c4a_4b7e_9d1f_2a3b4c5d6e7f.slice":                                                                    {"1d3b6f2e-5c4a-4b7e-9d1f-2a3b4c5d6e7f", ""},
+		"kubepods/burstable/pod1d3b6f2e-5c4a-4b7e-9d1f-2a3b4c5d6e7f/" + id:                                                                         {"1d3b6f2e-5c4a-4b7e-9d1f-2a3b4c5d6e7f", id},
+		"kubepods.slice/kubepods-burstable.slice":                                                                                                  {"", ""},
+		"system.slice/docker-" + id + ".scope":                                                                                                     {"", ""},
+	} {
+		podUID, containerID := kubernetesIDsFromCgroup(cgroup)
+		if podUID != expected[0] || containerID != expected[1] {
+			t.Errorf("%s: expected %q %q, got %q %q", cgroup, expected[0], expected[1], podUID, containerID)
+		}
+	}
+}
+
+func TestWalkCgroups(t *testing.T) {
+	root := t.TempDir()
+
+	pod := "kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod1d3b6f2e_5c4a_4b7e_9d1f_2a3b4c5d6e7f.slice"
+	container := pod + "/cri-containerd-" + strings.Repeat("ab", 32) + ".scope"
+
+	writeSysFiles(t, root, map[string]string{
+		"system.slice/cpu.stat":                             "usage_usec 2000000\nuser_usec 1500000\nsystem_usec 500000",
+		"system.slice/ssh.service/cpu.stat":                 "usage_usec 100\nuser_usec 50\nsystem_usec 50\nnr_periods 10\nnr_throttled 2\nthrottled_usec 300",
+		"system.slice/ssh.service/memory.max":               "max",
+		"system.slice/ssh.service/pids.max":                 "512",
+		"system.slice/ssh.service/pids.current":             "3",
+		"kubepods.slice/cpu.stat":                           "usage_usec 1",
+		"kubepods.slice/kubepods-besteffort.slice/cpu.stat": "usage_usec 1",
+		pod + "/cpu.stat":                                   "usage_usec 1",
+		container + "/cpu.stat":                             "usage_usec 1",
+		container + "/memory.current":                       "1048576",
+		container + "/memory.max":                           "268435456",
+		container + "/memory.events":                        "low 0\nhigh 0\nmax 12\noom 1\noom_kill 1",
+		container + "/io.stat":                              "259:0 rbytes=4096 wbytes=8192 rios=1 wios=2 dbytes=0 dios=0",
+		container + "/nested/cpu.stat":                      "usage_usec 1",
+	})
+
+	stats, err := walkCgroups(root, []string{"system.slice", "kubepods.slice", "missing.slice"}, 3, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byCgroup := make(map[string]*CgroupStats)
+	for i := range stats {
+		byCgroup[stats[i].Cgroup] = stats[i]
+	}
+
+	if len(byCgroup) != 6 {
+		t.Errorf("expected 6 cgroups, got %d", len(byCgroup))
+	}
+
+	ssh := byCgroup["system.slice/ssh.service"]
+	if ssh == nil || ssh.CPU["nr_throttled"] != 2 || ssh.MemoryMax != -1 || ssh.PidsMax != 512 || ssh.PidsCurrent != 3 || ssh.PodUID != "" {
+		t.Errorf("unexpected ssh.service %+v", ssh)
+	}
+
+	c := byCgroup[container]
+	if c == nil {
+		t.Fatalf("container cgroup not collected")
+	}
+
+	if c.PodUID != "1d3b6f2e-5c4a-4b7e-9d1f-2a3b4c5d6e7f" || c.ContainerID != strings.Repeat("ab", 32) {
+		t.Errorf("unexpected kubernetes ids %q %q", c.PodUID, c.ContainerID)
+	}
+
+	if c.MemoryCurrent != 1048576 || c.MemoryMax != 268435456 || c.MemoryEvents["oom_kill"] != 1 || len(c.IO) != 1 || c.IO[0].Fields["wbytes"] != 8192 {
+		t.Errorf("unexpected container %+v", c)
+	}
+
+	if _, ok := byCgroup[container+"/nested"]; ok {
+		t.Error("expected cgroups below max_depth to be skipped")
+	}
+}
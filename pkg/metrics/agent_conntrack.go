@@ -0,0 +1,104 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Conntrack netfilter connection tracking table usage and per cpu statistics
+type Conntrack struct {
+	Entries      float64
+	EntriesLimit float64
+	// Stats per cpu from /proc/net/stat/nf_conntrack, keyed by the column header (insert_failed, drop, early_drop, etc)
+	Stats []ConntrackCPUStats
+}
+
+// ConntrackCPUStats a single cpu (line) from /proc/net/stat/nf_conntrack
+type ConntrackCPUStats struct {
+	CPU    string
+	Fields map[string]uint64
+}
+
+// getConntrack reads nf_conntrack_count, nf_conntrack_max and /proc/net/stat/nf_conntrack
+//
+// returns os.ErrNotExist if the nf_conntrack module is not loaded
+func getConntrack() (*Conntrack, error) {
+	var ct Conntrack
+	var err error
+
+	if ct.Entries, err = readSysFloat("/proc/sys/net/netfilter/nf_conntrack_count"); err != nil {
+		return nil, err
+	}
+
+	if ct.EntriesLimit, err = readSysFloat("/proc/sys/net/netfilter/nf_conntrack_max"); err != nil {
+		return nil, err
+	}
+
+	statFD, err := os.Open("/proc/net/stat/nf_conntrack")
+	if err != nil {
+		return nil, err
+	}
+	defer statFD.Close() //nolint
+
+	if ct.Stats, err = parseConntrackStat(statFD); err != nil {
+		return nil, err
+	}
+
+	return &ct, nil
+}
+
+// parseConntrackStat parses /proc/net/stat/nf_conntrack, a header followed by one line of hex values per cpu
+func parseConntrackStat(r io.Reader) ([]ConntrackCPUStats, error) {
+	reader := bufio.NewReader(r)
+
+	data, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+
+	header := strings.Fields(data)
+
+	var stats []ConntrackCPUStats
+
+	for cpu := 0; ; cpu++ {
+		data, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		splitted := strings.Fields(data)
+
+		if len(splitted) > 0 {
+			if len(splitted) != len(header) {
+				return nil, fmt.Errorf("conntrack: expecting %d fields, got %d", len(header), len(splitted))
+			}
+
+			cs := ConntrackCPUStats{
+				CPU:    fmt.Sprintf("cpu%d", cpu),
+				Fields: make(map[string]uint64),
+			}
+
+			for i := range splitted {
+				val, errParse := strconv.ParseUint(splitted[i], 16, 64)
+				if errParse != nil {
+					return nil, errParse
+				}
+
+				cs.Fields[header[i]] = val
+			}
+
+			stats = append(stats, cs)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,40 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGetConntrack(t *testing.T) {
+	_, err := getConntrack()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Error(err)
+	}
+}
+
+func TestParseConntrackStat(t *testing.T) {
+	data := `entries  clashres found new invalid ignore delete chainlength insert insert_failed drop early_drop icmp_error  expect_new expect_create expect_delete search_restart
+00000036  00000000 00000000 00000000 0000001a 00000000 00000000 00000000 00000000 00000002 0000000a 00000000 00000000  00000000 00000000 00000000 00000003
+00000036  00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 000000ff 00000000 00000000  00000000 00000000 00000000 00000000
+`
+
+	stats, err := parseConntrackStat(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("expect 2 cpus, got %d", len(stats))
+	}
+
+	if stats[0].CPU != "cpu0" || stats[0].Fields["entries"] != 0x36 || stats[0].Fields["invalid"] != 0x1a || stats[0].Fields["insert_failed"] != 2 || stats[0].Fields["search_restart"] != 3 {
+		t.Errorf("unexpected cpu0: %+v", stats[0])
+	}
+
+	if stats[1].CPU != "cpu1" || stats[1].Fields["drop"] != 0xff {
+		t.Errorf("unexpected cpu1: %+v", stats[1])
+	}
+}
@@ -13,100 +13,194 @@ import (
 
 // ProcStatCPU container for CPU metrics
 type ProcStatCPU struct {
+	CPU                                                                     string
 	User, Nice, System, Idle, IOWait, IRQ, SoftIRQ, Steal, Guest, GuestNice int
 }
 
+// ProcStat container for /proc/stat
+//
+// https://www.kernel.org/doc/html/latest/filesystems/proc.html#miscellaneous-kernel-statistics-in-proc-stat
+type ProcStat struct {
+	CPU             ProcStatCPU
+	CPUs            []ProcStatCPU
+	Interrupts      uint64
+	ContextSwitches uint64
+	BootTime        uint64
+	Forks           uint64
+	ProcsRunning    uint64
+	ProcsBlocked    uint64
+}
+
 var prevCPU ProcStatCPU
+var prevCPUs = make(map[string]ProcStatCPU)
 
-func getCPUUtil() (*ProcStatCPU, error) {
-	cpuStat1, err := getProcStat()
-	if err != nil {
-		return nil, err
-	}
+// getCPUUtil returns the aggregate utilization of procStat since the previous call
+func getCPUUtil(procStat *ProcStat) *ProcStatCPU {
+	cpuStat1 := procStat.CPU
 
-	stat := &ProcStatCPU{
-		User:      cpuStat1.User - prevCPU.User,
-		Nice:      cpuStat1.Nice - prevCPU.Nice,
-		System:    cpuStat1.System - prevCPU.System,
-		Idle:      cpuStat1.Idle - prevCPU.Idle,
-		IOWait:    cpuStat1.IOWait - prevCPU.IOWait,
-		IRQ:       cpuStat1.IRQ - prevCPU.IRQ,
-		SoftIRQ:   cpuStat1.SoftIRQ - prevCPU.SoftIRQ,
-		Steal:     cpuStat1.Steal - prevCPU.Steal,
-		Guest:     cpuStat1.Guest - prevCPU.Guest,
-		GuestNice: cpuStat1.GuestNice - prevCPU.GuestNice,
-	}
+	stat := cpuDelta(&cpuStat1, &prevCPU)
 
-	prevCPU = *cpuStat1
+	prevCPU = cpuStat1
 
-	return stat, nil
+	return stat
 }
 
-func getProcStat() (*ProcStatCPU, error) {
-	procStat, err := os.Open("/proc/stat")
-	if err != nil {
-		return nil, err
-	}
-	defer procStat.Close() //nolint
+// getPerCPUUtil returns the per-core utilization of procStat since the previous call
+//
+// cores seen for the first time are compared against zero, same as the aggregate on first run
+func getPerCPUUtil(procStat *ProcStat) []*ProcStatCPU {
+	var stats []*ProcStatCPU
 
-	reader := bufio.NewReader(procStat)
+	seen := make(map[string]ProcStatCPU)
 
-	data, err := reader.ReadString('\n')
-	if err != nil && err != io.EOF {
-		return nil, err
-	}
+	for i := range procStat.CPUs {
+		prev := prevCPUs[procStat.CPUs[i].CPU]
 
-	splitted := strings.Fields(data)
+		stats = append(stats, cpuDelta(&procStat.CPUs[i], &prev))
 
-	if splitted[0] != "cpu" {
-		return nil, fmt.Errorf("metrics: expecting first field to be 'cpu' when reading /proc/stat")
+		seen[procStat.CPUs[i].CPU] = procStat.CPUs[i]
 	}
 
-	if len(splitted) != 11 { //nolint
-		return nil, fmt.Errorf("metrics: expecting 'cpu' from /proc/stat to be 11 (%d)", len(splitted))
-	}
+	// cores that went offline are dropped
+	prevCPUs = seen
 
-	var cpuStats ProcStatCPU
-	var errAtoi error
+	return stats
+}
 
-	if cpuStats.User, errAtoi = strconv.Atoi(splitted[1]); errAtoi != nil {
-		return nil, errAtoi
+func cpuDelta(cur, prev *ProcStatCPU) *ProcStatCPU {
+	return &ProcStatCPU{
+		CPU:       cur.CPU,
+		User:      cur.User - prev.User,
+		Nice:      cur.Nice - prev.Nice,
+		System:    cur.System - prev.System,
+		Idle:      cur.Idle - prev.Idle,
+		IOWait:    cur.IOWait - prev.IOWait,
+		IRQ:       cur.IRQ - prev.IRQ,
+		SoftIRQ:   cur.SoftIRQ - prev.SoftIRQ,
+		Steal:     cur.Steal - prev.Steal,
+		Guest:     cur.Guest - prev.Guest,
+		GuestNice: cur.GuestNice - prev.GuestNice,
 	}
+}
 
-	if cpuStats.Nice, errAtoi = strconv.Atoi(splitted[2]); errAtoi != nil {
-		return nil, errAtoi
+func getProcStat() (*ProcStat, error) {
+	procStat, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil, err
 	}
+	defer procStat.Close() //nolint
 
-	if cpuStats.System, errAtoi = strconv.Atoi(splitted[3]); errAtoi != nil {
-		return nil, errAtoi
-	}
+	return parseProcStat(procStat)
+}
 
-	if cpuStats.Idle, errAtoi = strconv.Atoi(splitted[4]); errAtoi != nil {
-		return nil, errAtoi
-	}
+// parseProcStat parses the contents of /proc/stat
+func parseProcStat(r io.Reader) (*ProcStat, error) {
+	reader := bufio.NewReader(r)
+
+	var stat ProcStat
+	var foundCPU bool
+
+	for {
+		data, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		splitted := strings.Fields(data)
+
+		if len(splitted) >= 2 { //nolint
+			switch {
+			case splitted[0] == "cpu":
+				cpuStats, errCPU := parseProcStatCPU(splitted)
+				if errCPU != nil {
+					return nil, errCPU
+				}
+
+				stat.CPU = *cpuStats
+				foundCPU = true
+			case strings.HasPrefix(splitted[0], "cpu"):
+				cpuStats, errCPU := parseProcStatCPU(splitted)
+				if errCPU != nil {
+					return nil, errCPU
+				}
+
+				stat.CPUs = append(stat.CPUs, *cpuStats)
+			case splitted[0] == "intr":
+				// first value is the total, the rest are per irq
+				if stat.Interrupts, err = strconv.ParseUint(splitted[1], 10, 64); err != nil {
+					return nil, err
+				}
+			case splitted[0] == "ctxt":
+				if stat.ContextSwitches, err = strconv.ParseUint(splitted[1], 10, 64); err != nil {
+					return nil, err
+				}
+			case splitted[0] == "btime":
+				if stat.BootTime, err = strconv.ParseUint(splitted[1], 10, 64); err != nil {
+					return nil, err
+				}
+			case splitted[0] == "processes":
+				if stat.Forks, err = strconv.ParseUint(splitted[1], 10, 64); err != nil {
+					return nil, err
+				}
+			case splitted[0] == "procs_running":
+				if stat.ProcsRunning, err = strconv.ParseUint(splitted[1], 10, 64); err != nil {
+					return nil, err
+				}
+			case splitted[0] == "procs_blocked":
+				if stat.ProcsBlocked, err = strconv.ParseUint(splitted[1], 10, 64); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if !foundCPU {
+		return nil, fmt.Errorf("metrics: expecting 'cpu' line when reading /proc/stat")
+	}
+
+	return &stat, nil
+}
 
-	if cpuStats.IOWait, errAtoi = strconv.Atoi(splitted[5]); errAtoi != nil {
-		return nil, errAtoi
+// parseProcStatCPU parses a cpu/cpuN line from /proc/stat
+//
+// older kernels expose fewer columns (steal since 2.6.11, guest since 2.6.24, guest_nice since 2.6.33), missing columns are left at 0
+func parseProcStatCPU(splitted []string) (*ProcStatCPU, error) {
+	if len(splitted) < 5 { //nolint
+		return nil, fmt.Errorf("metrics: expecting %q from /proc/stat to have at least 5 fields (%d)", splitted[0], len(splitted))
 	}
 
-	if cpuStats.IRQ, errAtoi = strconv.Atoi(splitted[6]); errAtoi != nil {
-		return nil, errAtoi
+	cpuStats := ProcStatCPU{
+		CPU: splitted[0],
 	}
 
-	if cpuStats.SoftIRQ, errAtoi = strconv.Atoi(splitted[7]); errAtoi != nil {
-		return nil, errAtoi
+	fields := []*int{
+		&cpuStats.User,
+		&cpuStats.Nice,
+		&cpuStats.System,
+		&cpuStats.Idle,
+		&cpuStats.IOWait,
+		&cpuStats.IRQ,
+		&cpuStats.SoftIRQ,
+		&cpuStats.Steal,
+		&cpuStats.Guest,
+		&cpuStats.GuestNice,
 	}
 
-	if cpuStats.Steal, errAtoi = strconv.Atoi(splitted[8]); errAtoi != nil {
-		return nil, errAtoi
-	}
+	for i := range fields {
+		if i+1 >= len(splitted) {
+			break
+		}
 
-	if cpuStats.Guest, errAtoi = strconv.Atoi(splitted[9]); errAtoi != nil {
-		return nil, errAtoi
-	}
+		val, errAtoi := strconv.Atoi(splitted[i+1])
+		if errAtoi != nil {
+			return nil, errAtoi
+		}
 
-	if cpuStats.GuestNice, errAtoi = strconv.Atoi(splitted[10]); errAtoi != nil {
-		return nil, errAtoi
+		*fields[i] = val
 	}
 
 	return &cpuStats, nil
@@ -3,18 +3,31 @@ package metrics
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestGetCPUUtil(t *testing.T) {
-	p, err := getCPUUtil()
+	prevCPU = ProcStatCPU{}
+
+	procStat, err := getProcStat()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
+	p := getCPUUtil(procStat)
+
 	if !reflect.DeepEqual(*p, prevCPU) {
 		t.Errorf("expect prevCPU and p to be equal")
 	}
+
+	next := *procStat
+	next.CPU.User += 10
+	next.CPU.Idle += 30
+
+	if p := getCPUUtil(&next); p.User != 10 || p.Idle != 30 || p.System != 0 {
+		t.Errorf("unexpected delta %+v", p)
+	}
 }
 
 func TestGetProcStat(t *testing.T) {
@@ -30,3 +43,55 @@ func TestGetHostCPUs(t *testing.T) {
 		t.Error("expect at least 1 cpu")
 	}
 }
+
+func TestGetPerCPUUtil(t *testing.T) {
+	procStat, err := getProcStat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := getPerCPUUtil(procStat)
+
+	if len(p) != len(prevCPUs) {
+		t.Errorf("expect prevCPUs to track every core (%d != %d)", len(p), len(prevCPUs))
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	data := `cpu  10132153 290696 3084719 46828483 16683 0 25195 0 0 0
+cpu0 1393280 32966 572056 13343292 6130 0 17875 0 0 0
+cpu1 1335 0 1245 2222 45 0 12
+intr 199292 42 0 0
+ctxt 38014093
+btime 1709539911
+processes 26442
+procs_running 2
+procs_blocked 1
+softirq 12121 0 1 2
+`
+
+	stat, err := parseProcStat(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stat.CPU.User != 10132153 || stat.CPU.IOWait != 16683 {
+		t.Errorf("unexpected aggregate cpu: %+v", stat.CPU)
+	}
+
+	if len(stat.CPUs) != 2 {
+		t.Fatalf("expect 2 cpus, got %d", len(stat.CPUs))
+	}
+
+	if stat.CPUs[1].CPU != "cpu1" || stat.CPUs[1].SoftIRQ != 12 || stat.CPUs[1].Steal != 0 {
+		t.Errorf("unexpected cpu1 (short line): %+v", stat.CPUs[1])
+	}
+
+	if stat.Interrupts != 199292 || stat.ContextSwitches != 38014093 || stat.BootTime != 1709539911 {
+		t.Errorf("unexpected counters: %+v", stat)
+	}
+
+	if stat.Forks != 26442 || stat.ProcsRunning != 2 || stat.ProcsBlocked != 1 {
+		t.Errorf("unexpected procs: %+v", stat)
+	}
+}
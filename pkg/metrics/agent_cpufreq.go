@@ -0,0 +1,98 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+const sysDevicesCPUPath = "/sys/devices/system/cpu"
+
+var cpuDirRe = regexp.MustCompile(`^cpu([0-9]+)$`)
+
+// CPUFreqStats frequency and throttling of a single cpu
+//
+// frequencies are -1 if cpufreq isn't available (most VMs), throttle counts are -1 without thermal_throttle (non intel)
+type CPUFreqStats struct {
+	CPU      string
+	Package  string
+	CurHz    float64
+	MinHz    float64
+	MaxHz    float64
+	Governor string
+
+	CoreThrottles         float64
+	CoreThrottleTimeMS    float64
+	PackageThrottles      float64 // the same for every cpu in the package
+	PackageThrottleTimeMS float64
+}
+
+// getCPUFreqStats reads cpufreq and thermal_throttle for every cpu under root (/sys/devices/system/cpu)
+func getCPUFreqStats(root string) ([]*CPUFreqStats, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var stats []*CPUFreqStats
+
+	for i := range entries {
+		m := cpuDirRe.FindStringSubmatch(entries[i].Name())
+		if m == nil {
+			continue
+		}
+
+		dir := filepath.Join(root, entries[i].Name())
+
+		// offline cpus have no cpufreq or topology
+		if _, err := os.Stat(filepath.Join(dir, "topology")); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		cs := CPUFreqStats{
+			CPU:                   fmt.Sprintf("cpu%s", m[1]),
+			Package:               "0",
+			CurHz:                 readKHzAsHz(filepath.Join(dir, "cpufreq", "scaling_cur_freq")),
+			MinHz:                 readKHzAsHz(filepath.Join(dir, "cpufreq", "cpuinfo_min_freq")),
+			MaxHz:                 readKHzAsHz(filepath.Join(dir, "cpufreq", "cpuinfo_max_freq")),
+			CoreThrottles:         readSysFloatOr(filepath.Join(dir, "thermal_throttle", "core_throttle_count"), -1),
+			CoreThrottleTimeMS:    readSysFloatOr(filepath.Join(dir, "thermal_throttle", "core_throttle_total_time_ms"), -1),
+			PackageThrottles:      readSysFloatOr(filepath.Join(dir, "thermal_throttle", "package_throttle_count"), -1),
+			PackageThrottleTimeMS: readSysFloatOr(filepath.Join(dir, "thermal_throttle", "package_throttle_total_time_ms"), -1),
+		}
+
+		if pkg, err := readSysString(filepath.Join(dir, "topology", "physical_package_id")); err == nil {
+			cs.Package = pkg
+		}
+
+		if governor, err := readSysString(filepath.Join(dir, "cpufreq", "scaling_governor")); err == nil {
+			cs.Governor = governor
+		}
+
+		stats = append(stats, &cs)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		a, _ := strconv.Atoi(stats[i].CPU[3:])
+		b, _ := strconv.Atoi(stats[j].CPU[3:])
+
+		return a < b
+	})
+
+	return stats, nil
+}
+
+// readKHzAsHz reads a cpufreq kHz value, -1 if unavailable
+func readKHzAsHz(path string) float64 {
+	v, err := readSysFloat(path)
+	if err != nil {
+		return -1
+	}
+
+	return v * 1000 //nolint
+}
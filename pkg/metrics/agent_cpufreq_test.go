@@ -0,0 +1,58 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"testing"
+)
+
+func TestGetCPUFreqStats(t *testing.T) {
+	root := t.TempDir()
+
+	writeSysFiles(t, root, map[string]string{
+		"cpu0/topology/physical_package_id":                 "0",
+		"cpu0/cpufreq/scaling_cur_freq":                     "2400000",
+		"cpu0/cpufreq/cpuinfo_min_freq":                     "800000",
+		"cpu0/cpufreq/cpuinfo_max_freq":                     "3500000",
+		"cpu0/cpufreq/scaling_governor":                     "performance",
+		"cpu0/thermal_throttle/core_throttle_count":         "3",
+		"cpu0/thermal_throttle/core_throttle_total_time_ms": "120",
+		"cpu0/thermal_throttle/package_throttle_count":      "7",
+		"cpu10/topology/physical_package_id":                "1",
+		"cpu2/topology/physical_package_id":                 "0",
+		"cpufreq/boost":                                     "1",
+		"cpu3/online":                                       "0",
+	})
+
+	stats, err := getCPUFreqStats(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 cpus, got %d", len(stats))
+	}
+
+	if stats[0].CPU != "cpu0" || stats[1].CPU != "cpu2" || stats[2].CPU != "cpu10" {
+		t.Errorf("unexpected cpu order %s %s %s", stats[0].CPU, stats[1].CPU, stats[2].CPU)
+	}
+
+	cpu0 := stats[0]
+	if cpu0.CurHz != 2.4e9 || cpu0.MinHz != 8e8 || cpu0.MaxHz != 3.5e9 || cpu0.Governor != "performance" {
+		t.Errorf("unexpected cpufreq %+v", cpu0)
+	}
+
+	if cpu0.CoreThrottles != 3 || cpu0.CoreThrottleTimeMS != 120 || cpu0.PackageThrottles != 7 || cpu0.PackageThrottleTimeMS != -1 {
+		t.Errorf("unexpected thermal_throttle %+v", cpu0)
+	}
+
+	if stats[2].Package != "1" || stats[2].CurHz != -1 || stats[2].CoreThrottles != -1 || stats[2].Governor != "" {
+		t.Errorf("unexpected cpu10 %+v", stats[2])
+	}
+}
+
+func TestGetCPUFreqStatsHost(t *testing.T) {
+	_, err := getCPUFreqStats(sysDevicesCPUPath)
+	if err != nil {
+		t.Error(err)
+	}
+}
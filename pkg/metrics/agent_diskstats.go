@@ -3,11 +3,14 @@ package metrics
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"os"
-	"regexp"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/vultr/v-agent/cmd/v-agent/config"
 
@@ -19,6 +22,7 @@ import (
 // https://www.kernel.org/doc/Documentation/iostats.txt
 type DiskStats struct {
 	Device                 string
+	ID                     string // /dev/disk/by-id name, empty if the device has none
 	Reads                  uint64
 	ReadsMerged            uint64
 	SectorsRead            uint64
@@ -34,9 +38,27 @@ type DiskStats struct {
 	DiscardsMerged         uint64
 	SectorsDiscarded       uint64
 	MillisecondsDiscarding uint64
+
+	// derived over the gather interval, as computed by iostat -x
+	ReadBytes  float64 // bytes/s
+	WriteBytes float64 // bytes/s
+	ReadIOPS   float64
+	WriteIOPS  float64
+	ReadAwait  float64 // ms
+	WriteAwait float64 // ms
+	QueueDepth float64 // average requests in flight (aqu-sz)
+	Util       float64 // percentage of time the device had I/O in flight
 }
 
-var prevDS []*DiskStats
+const (
+	diskByIDPath = "/dev/disk/by-id"
+	sectorSize   = 512 // /proc/diskstats always counts 512 byte sectors, regardless of the device sector size
+)
+
+var (
+	prevDS     []*DiskStats
+	prevDSTime time.Time
+)
 
 func getDiskStatsUtil() ([]*DiskStats, error) {
 	diskStat1, err := getDiskStats()
@@ -44,13 +66,17 @@ func getDiskStatsUtil() ([]*DiskStats, error) {
 		return nil, err
 	}
 
+	now := time.Now()
+	elapsed := now.Sub(prevDSTime)
+
 	var ds []*DiskStats
 
 	for i := range diskStat1 {
 		for j := range prevDS {
 			if diskStat1[i].Device == prevDS[j].Device {
-				ds = append(ds, &DiskStats{
+				d := &DiskStats{
 					Device:                 diskStat1[i].Device,
+					ID:                     diskStat1[i].ID,
 					Reads:                  diskStat1[i].Reads - prevDS[j].Reads,
 					ReadsMerged:            diskStat1[i].ReadsMerged - prevDS[j].ReadsMerged,
 					SectorsRead:            diskStat1[i].SectorsRead - prevDS[j].SectorsRead,
@@ -66,20 +92,54 @@ func getDiskStatsUtil() ([]*DiskStats, error) {
 					DiscardsMerged:         diskStat1[i].DiscardsMerged - prevDS[j].DiscardsMerged,
 					SectorsDiscarded:       diskStat1[i].SectorsDiscarded - prevDS[j].SectorsDiscarded,
 					MillisecondsDiscarding: diskStat1[i].MillisecondsDiscarding - prevDS[j].MillisecondsDiscarding,
-				})
+				}
+
+				deriveDiskStats(d, elapsed)
+
+				ds = append(ds, d)
 			}
 		}
 	}
 
 	prevDS = diskStat1
+	prevDSTime = now
 
 	return ds, nil
 }
 
-// getDiskStats reads /proc/diskstats
-func getDiskStats() ([]*DiskStats, error) {
-	log := zap.L().Sugar()
+// deriveDiskStats computes throughput, latency and utilization from the deltas in d over elapsed
+func deriveDiskStats(d *DiskStats, elapsed time.Duration) {
+	secs := elapsed.Seconds()
+	if secs <= 0 {
+		return
+	}
+
+	ms := secs * 1000 //nolint
+
+	d.ReadBytes = float64(d.SectorsRead*sectorSize) / secs
+	d.WriteBytes = float64(d.SectorsWritten*sectorSize) / secs
+	d.ReadIOPS = float64(d.Reads) / secs
+	d.WriteIOPS = float64(d.WritesCompleted) / secs
+
+	if d.Reads > 0 {
+		d.ReadAwait = float64(d.MillisecondsReading) / float64(d.Reads)
+	}
 
+	if d.WritesCompleted > 0 {
+		d.WriteAwait = float64(d.MillisecondsWriting) / float64(d.WritesCompleted)
+	}
+
+	d.QueueDepth = float64(d.WeightedIOsInMS) / ms
+
+	// io_ticks can run slightly ahead of wall clock
+	d.Util = float64(d.MillisecondsInIOs) / ms * 100
+	if d.Util > 100 { //nolint
+		d.Util = 100
+	}
+}
+
+// getDiskStats reads /proc/diskstats, applying the disk_stats include/exclude filters
+func getDiskStats() ([]*DiskStats, error) {
 	_, err := os.Stat("/proc/diskstats")
 	if err != nil {
 		return nil, err
@@ -91,133 +151,128 @@ func getDiskStats() ([]*DiskStats, error) {
 	}
 	defer procDiskStatsFD.Close() //nolint
 
-	reader := bufio.NewReader(procDiskStatsFD)
+	filter, err := newIncludeExcludeFilter(config.GetDiskStatsInclude(), config.GetDiskStatsExclude())
+	if err != nil {
+		return nil, err
+	}
 
-	var diskStats []*DiskStats
+	diskStats, err := parseDiskStats(procDiskStatsFD, filter)
+	if err != nil {
+		return nil, err
+	}
 
-	filter := config.GetDiskStatsFilter()
+	ids := getDiskIDs(diskByIDPath)
 
-	re := regexp.MustCompile(filter)
+	for i := range diskStats {
+		diskStats[i].ID = ids[diskStats[i].Device]
+	}
+
+	return diskStats, nil
+}
+
+// parseDiskStats parses /proc/diskstats:
+//
+//	252       0 vda 9120 2840 755446 3517 57180 28866 2350066 25417 0 51528 30440 0 0 0 0 2051 1506
+//
+// kernels before 4.18 don't report the discard fields, 5.5+ append flush fields which are ignored
+func parseDiskStats(r io.Reader, filter *includeExcludeFilter) ([]*DiskStats, error) {
+	log := zap.L().Sugar()
+
+	reader := bufio.NewReader(r)
+
+	var diskStats []*DiskStats
 
 	for {
 		data, err := reader.ReadString('\n')
 		if err != nil && err != io.EOF {
 			return nil, err
 		}
-		if err == io.EOF {
-			break
-		}
 
 		splitted := strings.Fields(data)
-		if len(splitted) != 18 { //nolint
-			break
-		}
 
-		device := splitted[2]
+		if len(splitted) >= 14 { //nolint
+			device := splitted[2]
 
-		if re.MatchString(device) {
-			log.Infof("skipping device %q", device)
-			continue
-		}
+			if !filter.match(device) {
+				log.Debugf("skipping device %q", device)
+			} else {
+				var values [15]uint64
 
-		readsS := splitted[3]
-		readsSM := splitted[4]
-		sectorsRS := splitted[5]
-		msRdS := splitted[6]
-		writesCompletedS := splitted[7]
-		writesMergedS := splitted[8]
-		sectorsWrS := splitted[9]
-		msWrS := splitted[10]
-		ioInProgressS := splitted[11]
-		msInIOsS := splitted[12]
-		weightedIOsInMSS := splitted[13]
-		discardsS := splitted[14]
-		discardsMergedS := splitted[15]
-		sectorsDiscardedS := splitted[16]
-		msDiscardingS := splitted[17]
-
-		reads, err := strconv.ParseUint(readsS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		readsM, err := strconv.ParseUint(readsSM, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		sectorsRd, err := strconv.ParseUint(sectorsRS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		msRd, err := strconv.ParseUint(msRdS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		writesCompleted, err := strconv.ParseUint(writesCompletedS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		writesMerged, err := strconv.ParseUint(writesMergedS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		sectorsWr, err := strconv.ParseUint(sectorsWrS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		msWr, err := strconv.ParseUint(msWrS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		ioInProgress, err := strconv.ParseUint(ioInProgressS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		msInIOs, err := strconv.ParseUint(msInIOsS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		weightedIOsInMS, err := strconv.ParseUint(weightedIOsInMSS, 10, 64)
-		if err != nil {
-			return nil, err
-		}
-		discards, err := strconv.ParseUint(discardsS, 10, 64)
-		if err != nil {
-			return nil, err
+				for i := 0; i < len(values) && i+3 < len(splitted); i++ {
+					v, errParse := strconv.ParseUint(splitted[i+3], 10, 64)
+					if errParse != nil {
+						return nil, fmt.Errorf("%s: %w", device, errParse)
+					}
+
+					values[i] = v
+				}
+
+				diskStats = append(diskStats, &DiskStats{
+					Device:                 device,
+					Reads:                  values[0],
+					ReadsMerged:            values[1],
+					SectorsRead:            values[2],
+					MillisecondsReading:    values[3],
+					WritesCompleted:        values[4],
+					WritesMerged:           values[5],
+					SectorsWritten:         values[6],
+					MillisecondsWriting:    values[7],
+					IOsInProgress:          values[8],
+					MillisecondsInIOs:      values[9],
+					WeightedIOsInMS:        values[10],
+					Discards:               values[11],
+					DiscardsMerged:         values[12],
+					SectorsDiscarded:       values[13],
+					MillisecondsDiscarding: values[14],
+				})
+			}
 		}
-		discardsMerged, err := strconv.ParseUint(discardsMergedS, 10, 64)
-		if err != nil {
-			return nil, err
+
+		if err == io.EOF {
+			break
 		}
-		sectorsDiscarded, err := strconv.ParseUint(sectorsDiscardedS, 10, 64)
+	}
+
+	return diskStats, nil
+}
+
+// getDiskIDs maps kernel device names (sda, nvme0n1p1) to a name in /dev/disk/by-id
+//
+// a device usually has several links, wwn- and nvme-eui. names are preferred as they're tied to the hardware, otherwise the first sorted name is used
+func getDiskIDs(dir string) map[string]string {
+	ids := make(map[string]string)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// not present without udev (containers, minimal images)
+		return ids
+	}
+
+	names := make([]string, 0, len(entries))
+	for i := range entries {
+		names = append(names, entries[i].Name())
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		target, err := os.Readlink(filepath.Join(dir, name))
 		if err != nil {
-			return nil, err
+			continue
 		}
-		msDiscarding, err := strconv.ParseUint(msDiscardingS, 10, 64)
-		if err != nil {
-			return nil, err
+
+		device := filepath.Base(target)
+
+		if existing, ok := ids[device]; ok && (isPreferredDiskID(existing) || !isPreferredDiskID(name)) {
+			continue
 		}
 
-		var ds DiskStats
-
-		ds.Device = device
-		ds.Reads = reads
-		ds.ReadsMerged = readsM
-		ds.SectorsRead = sectorsRd
-		ds.MillisecondsReading = msRd
-		ds.WritesCompleted = writesCompleted
-		ds.WritesMerged = writesMerged
-		ds.SectorsWritten = sectorsWr
-		ds.MillisecondsWriting = msWr
-		ds.IOsInProgress = ioInProgress
-		ds.MillisecondsInIOs = msInIOs
-		ds.WeightedIOsInMS = weightedIOsInMS
-		ds.Discards = discards
-		ds.DiscardsMerged = discardsMerged
-		ds.SectorsDiscarded = sectorsDiscarded
-		ds.MillisecondsDiscarding = msDiscarding
-
-		diskStats = append(diskStats, &ds)
+		ids[device] = name
 	}
 
-	return diskStats, nil
+	return ids
+}
+
+func isPreferredDiskID(name string) bool {
+	return strings.HasPrefix(name, "wwn-") || strings.HasPrefix(name, "nvme-eui.")
 }
@@ -2,8 +2,11 @@
 package metrics
 
 import (
-	"reflect"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vultr/v-agent/cmd/v-agent/config"
 )
@@ -11,13 +14,18 @@ import (
 func TestGetDiskStatsUtil(t *testing.T) {
 	config.NewConfig("test", "v0.0.0") //nolint
 
+	// first call only primes prevDS
+	if _, err := getDiskStatsUtil(); err != nil {
+		t.Fatal(err)
+	}
+
 	p, err := getDiskStatsUtil()
 	if err != nil {
-		t.Error(err)
+		t.Fatal(err)
 	}
 
-	if !reflect.DeepEqual(p, prevDS) {
-		t.Errorf("expect prevDS and p to be equal")
+	if len(p) != len(prevDS) {
+		t.Errorf("expected a delta for each of the %d devices, got %d", len(prevDS), len(p))
 	}
 }
 
@@ -27,3 +35,90 @@ func TestGetDiskStats(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestParseDiskStats(t *testing.T) {
+	diskstats := `   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0
+ 252       0 vda 9120 2840 755446 3517 57180 28866 2350066 25417 0 51528 30440 0 0 0 0 2051 1506
+ 252       1 vda1 8900 2840 745000 3400 57000 28866 2350000 25400 0 51400 30300
+  11       0 sr0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0`
+
+	filter, err := newIncludeExcludeFilter("", "^(loop[0-9]+|sr[0-9]+)$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ds, err := parseDiskStats(strings.NewReader(diskstats), filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ds) != 2 {
+		t.Fatalf("expected 2 devices, got %d", len(ds))
+	}
+
+	if ds[0].Device != "vda" || ds[0].SectorsWritten != 2350066 || ds[0].MillisecondsInIOs != 51528 {
+		t.Errorf("unexpected stats %+v", ds[0])
+	}
+
+	// pre 4.18 kernels, no discard fields
+	if ds[1].Device != "vda1" || ds[1].WeightedIOsInMS != 30300 || ds[1].Discards != 0 {
+		t.Errorf("unexpected stats %+v", ds[1])
+	}
+}
+
+func TestDeriveDiskStats(t *testing.T) {
+	d := DiskStats{
+		Reads:               100,
+		SectorsRead:         2000,
+		MillisecondsReading: 250,
+		WritesCompleted:     50,
+		SectorsWritten:      400,
+		MillisecondsWriting: 500,
+		MillisecondsInIOs:   5000,
+		WeightedIOsInMS:     20000,
+	}
+
+	deriveDiskStats(&d, 10*time.Second)
+
+	for name, v := range map[string][2]float64{
+		"ReadBytes":  {d.ReadBytes, 102400},
+		"WriteBytes": {d.WriteBytes, 20480},
+		"ReadIOPS":   {d.ReadIOPS, 10},
+		"WriteIOPS":  {d.WriteIOPS, 5},
+		"ReadAwait":  {d.ReadAwait, 2.5},
+		"WriteAwait": {d.WriteAwait, 10},
+		"QueueDepth": {d.QueueDepth, 2},
+		"Util":       {d.Util, 50},
+	} {
+		if v[0] != v[1] {
+			t.Errorf("%s: expected %f, got %f", name, v[1], v[0])
+		}
+	}
+}
+
+func TestGetDiskIDs(t *testing.T) {
+	dir := t.TempDir()
+
+	for link, target := range map[string]string{
+		"ata-SAMSUNG_MZ7LH960_S45N":       "../../sda",
+		"wwn-0x5002538e40a1b2c3":          "../../sda",
+		"ata-SAMSUNG_MZ7LH960_S45N-part1": "../../sda1",
+		"virtio-vol-1234":                 "../../vdb",
+	} {
+		if err := os.Symlink(target, filepath.Join(dir, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ids := getDiskIDs(dir)
+
+	for device, id := range map[string]string{
+		"sda":  "wwn-0x5002538e40a1b2c3",
+		"sda1": "ata-SAMSUNG_MZ7LH960_S45N-part1",
+		"vdb":  "virtio-vol-1234",
+	} {
+		if ids[device] != id {
+			t.Errorf("%s: expected %q, got %q", device, id, ids[device])
+		}
+	}
+}
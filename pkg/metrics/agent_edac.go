@@ -0,0 +1,143 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+)
+
+const sysEDACMCPath = "/sys/devices/system/edac/mc"
+
+var (
+	edacMCDirRe    = regexp.MustCompile(`^mc([0-9]+)$`)
+	edacCSRowDirRe = regexp.MustCompile(`^csrow([0-9]+)$`)
+	edacDIMMDirRe  = regexp.MustCompile(`^(?:dimm|rank)([0-9]+)$`)
+)
+
+// EDACStats ECC error counts of a memory controller, its chip select rows and dimms
+//
+// https://docs.kernel.org/admin-guide/ras.html
+type EDACStats struct {
+	Controller string
+	Name       string // mc_name, the EDAC driver (skx_edac, ie31200, etc)
+
+	CECount       float64
+	UECount       float64
+	CENoInfoCount float64 // errors that couldn't be attributed to a csrow
+	UENoInfoCount float64
+
+	CSRows []*EDACErrorCounts
+	DIMMs  []*EDACErrorCounts
+}
+
+// EDACErrorCounts correctable and uncorrectable errors of a csrow or dimm
+type EDACErrorCounts struct {
+	ID      string
+	Label   string // dimm_label, empty for csrows
+	CECount float64
+	UECount float64
+}
+
+// getEDACStats reads every memory controller under root (/sys/devices/system/edac/mc)
+//
+// returns an empty list without EDAC (VMs, no EDAC driver loaded)
+func getEDACStats(root string) ([]*EDACStats, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var stats []*EDACStats
+
+	for i := range entries {
+		m := edacMCDirRe.FindStringSubmatch(entries[i].Name())
+		if m == nil {
+			continue
+		}
+
+		dir := filepath.Join(root, entries[i].Name())
+
+		es := EDACStats{Controller: m[1]}
+
+		es.Name, _ = readSysString(filepath.Join(dir, "mc_name"))
+
+		for _, f := range []struct {
+			name  string
+			value *float64
+		}{
+			{"ce_count", &es.CECount},
+			{"ue_count", &es.UECount},
+			{"ce_noinfo_count", &es.CENoInfoCount},
+			{"ue_noinfo_count", &es.UENoInfoCount},
+		} {
+			v, err := readSysFloat(filepath.Join(dir, f.name))
+			if err != nil {
+				return nil, err
+			}
+
+			*f.value = v
+		}
+
+		if es.CSRows, es.DIMMs, err = getEDACMCChildren(dir); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, &es)
+	}
+
+	return stats, nil
+}
+
+// getEDACMCChildren reads the csrow* and dimm* (rank* on some drivers) directories of a memory controller
+func getEDACMCChildren(dir string) ([]*EDACErrorCounts, []*EDACErrorCounts, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var csrows, dimms []*EDACErrorCounts
+
+	for i := range entries {
+		child := filepath.Join(dir, entries[i].Name())
+
+		if m := edacCSRowDirRe.FindStringSubmatch(entries[i].Name()); m != nil {
+			ec := EDACErrorCounts{ID: m[1]}
+
+			if ec.CECount, err = readSysFloat(filepath.Join(child, "ce_count")); err != nil {
+				return nil, nil, err
+			}
+
+			if ec.UECount, err = readSysFloat(filepath.Join(child, "ue_count")); err != nil {
+				return nil, nil, err
+			}
+
+			csrows = append(csrows, &ec)
+
+			continue
+		}
+
+		if m := edacDIMMDirRe.FindStringSubmatch(entries[i].Name()); m != nil {
+			ec := EDACErrorCounts{ID: m[1]}
+
+			ec.Label, _ = readSysString(filepath.Join(child, "dimm_label"))
+
+			if ec.CECount, err = readSysFloat(filepath.Join(child, "dimm_ce_count")); err != nil {
+				return nil, nil, err
+			}
+
+			if ec.UECount, err = readSysFloat(filepath.Join(child, "dimm_ue_count")); err != nil {
+				return nil, nil, err
+			}
+
+			dimms = append(dimms, &ec)
+		}
+	}
+
+	return csrows, dimms, nil
+}
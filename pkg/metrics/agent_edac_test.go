@@ -0,0 +1,74 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetEDACStats(t *testing.T) {
+	dir := t.TempDir()
+
+	writeSysFiles(t, dir, map[string]string{
+		"power/control":           "auto\n",
+		"mc0/mc_name":             "Skylake Socket#0 IMC#0\n",
+		"mc0/ce_count":            "7\n",
+		"mc0/ue_count":            "0\n",
+		"mc0/ce_noinfo_count":     "1\n",
+		"mc0/ue_noinfo_count":     "0\n",
+		"mc0/csrow0/ce_count":     "6\n",
+		"mc0/csrow0/ue_count":     "0\n",
+		"mc0/csrow0/ch0_ce_count": "6\n",
+		"mc0/dimm0/dimm_label":    "CPU_SrcID#0_MC#0_Chan#0_DIMM#0\n",
+		"mc0/dimm0/dimm_ce_count": "6\n",
+		"mc0/dimm0/dimm_ue_count": "0\n",
+		"mc0/dimm1/dimm_label":    "CPU_SrcID#0_MC#0_Chan#1_DIMM#0\n",
+		"mc0/dimm1/dimm_ce_count": "0\n",
+		"mc0/dimm1/dimm_ue_count": "1\n",
+		"mc1/mc_name":             "ie31200\n",
+		"mc1/ce_count":            "0\n",
+		"mc1/ue_count":            "2\n",
+		"mc1/ce_noinfo_count":     "0\n",
+		"mc1/ue_noinfo_count":     "0\n",
+		"mc1/rank0/dimm_label":    "DIMM_A1\n",
+		"mc1/rank0/dimm_ce_count": "0\n",
+		"mc1/rank0/dimm_ue_count": "2\n",
+	})
+
+	stats, err := getEDACStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 memory controllers, got %d", len(stats))
+	}
+
+	mc0 := stats[0]
+	if mc0.Controller != "0" || mc0.Name != "Skylake Socket#0 IMC#0" || mc0.CECount != 7 || mc0.CENoInfoCount != 1 {
+		t.Errorf("unexpected mc0 %+v", mc0)
+	}
+
+	if len(mc0.CSRows) != 1 || mc0.CSRows[0].ID != "0" || mc0.CSRows[0].CECount != 6 {
+		t.Errorf("unexpected mc0 csrows %+v", mc0.CSRows)
+	}
+
+	if len(mc0.DIMMs) != 2 || mc0.DIMMs[1].Label != "CPU_SrcID#0_MC#0_Chan#1_DIMM#0" || mc0.DIMMs[1].UECount != 1 {
+		t.Errorf("unexpected mc0 dimms %+v", mc0.DIMMs)
+	}
+
+	mc1 := stats[1]
+	if mc1.Name != "ie31200" || mc1.UECount != 2 || mc1.CSRows != nil || len(mc1.DIMMs) != 1 || mc1.DIMMs[0].Label != "DIMM_A1" {
+		t.Errorf("unexpected mc1 %+v", mc1)
+	}
+
+	if stats, err := getEDACStats(filepath.Join(dir, "missing")); err != nil || stats != nil {
+		t.Errorf("expected nil without edac, got %+v %v", stats, err)
+	}
+
+	writeSysFiles(t, dir, map[string]string{"mc1/ce_count": "x\n"})
+
+	if _, err := getEDACStats(dir); err == nil {
+		t.Error("expected error for malformed ce_count")
+	}
+}
@@ -0,0 +1,187 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"runtime"
+	"unsafe"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+
+	"go.uber.org/zap"
+	"golang.org/x/sys/unix"
+)
+
+const sysClassNetPath = "/sys/class/net"
+
+// ethtool string set and string length, not exposed by golang.org/x/sys/unix
+//
+// https://github.com/torvalds/linux/blob/master/include/uapi/linux/ethtool.h
+const (
+	ethSSStats    = 1
+	ethGStringLen = 32
+)
+
+// EthtoolStats link settings from /sys/class/net/<iface> and driver statistics from ethtool
+type EthtoolStats struct {
+	Interface string
+
+	// link settings, -1 if unknown (link down, virtual interface)
+	SpeedMbps float64
+	Duplex    string
+	Carrier   float64
+	MTU       float64
+	OperState string
+
+	// driver info and statistics, empty if the driver doesn't support ethtool
+	Driver          string
+	DriverVersion   string
+	FirmwareVersion string
+	BusInfo         string
+	Stats           map[string]uint64
+}
+
+// getEthtoolStats returns link settings and driver stats for interfaces matching ethtool.include/ethtool.exclude
+func getEthtoolStats() ([]*EthtoolStats, error) {
+	log := zap.L().Sugar()
+
+	filter, err := newIncludeExcludeFilter(config.GetEthtoolInclude(), config.GetEthtoolExclude())
+	if err != nil {
+		return nil, err
+	}
+
+	statsFilter, err := newIncludeExcludeFilter(config.GetEthtoolStatsFilter(), "")
+	if err != nil {
+		return nil, err
+	}
+
+	ifaces, err := os.ReadDir(sysClassNetPath)
+	if err != nil {
+		return nil, err
+	}
+
+	fd, err := unix.Socket(unix.AF_INET, unix.SOCK_DGRAM|unix.SOCK_CLOEXEC, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer unix.Close(fd) //nolint
+
+	var stats []*EthtoolStats
+
+	for i := range ifaces {
+		iface := ifaces[i].Name()
+
+		if !filter.match(iface) {
+			continue
+		}
+
+		es := getLinkSettings(iface)
+
+		if err := getEthtoolDriverStats(fd, es, statsFilter); err != nil {
+			// virtual interfaces (lo, veth, bridges) don't implement most of ethtool
+			log.Debugf("ethtool: %s: %s", iface, err)
+		}
+
+		stats = append(stats, es)
+	}
+
+	return stats, nil
+}
+
+// getLinkSettings reads link attributes from /sys/class/net/<iface>
+//
+// speed and carrier return EINVAL when the interface is down, those are reported as -1
+func getLinkSettings(iface string) *EthtoolStats {
+	es := EthtoolStats{
+		Interface: iface,
+		SpeedMbps: readSysFloatOr(filepath.Join(sysClassNetPath, iface, "speed"), -1),
+		Duplex:    readSysStringOr(filepath.Join(sysClassNetPath, iface, "duplex"), "unknown"),
+		Carrier:   readSysFloatOr(filepath.Join(sysClassNetPath, iface, "carrier"), -1),
+		MTU:       readSysFloatOr(filepath.Join(sysClassNetPath, iface, "mtu"), -1),
+		OperState: readSysStringOr(filepath.Join(sysClassNetPath, iface, "operstate"), "unknown"),
+	}
+
+	return &es
+}
+
+// getEthtoolDriverStats populates driver info and statistics (ethtool -i, ethtool -S)
+func getEthtoolDriverStats(fd int, es *EthtoolStats, statsFilter *includeExcludeFilter) error {
+	drvinfo, err := unix.IoctlGetEthtoolDrvinfo(fd, es.Interface)
+	if err != nil {
+		return err
+	}
+
+	es.Driver = unix.ByteSliceToString(drvinfo.Driver[:])
+	es.DriverVersion = unix.ByteSliceToString(drvinfo.Version[:])
+	es.FirmwareVersion = unix.ByteSliceToString(drvinfo.Fw_version[:])
+	es.BusInfo = unix.ByteSliceToString(drvinfo.Bus_info[:])
+
+	nStats := drvinfo.N_stats
+	if nStats == 0 {
+		return nil
+	}
+
+	// struct ethtool_gstrings: cmd, string_set, len, data[len * ETH_GSTRING_LEN]
+	gstrings := make([]byte, 12+int(nStats)*ethGStringLen)
+	binary.NativeEndian.PutUint32(gstrings[0:], unix.ETHTOOL_GSTRINGS)
+	binary.NativeEndian.PutUint32(gstrings[4:], ethSSStats)
+	binary.NativeEndian.PutUint32(gstrings[8:], nStats)
+
+	if err := ethtoolIoctl(fd, es.Interface, gstrings); err != nil {
+		return err
+	}
+
+	// struct ethtool_stats: cmd, n_stats, data[n_stats]
+	gstats := make([]byte, 8+int(nStats)*8)
+	binary.NativeEndian.PutUint32(gstats[0:], unix.ETHTOOL_GSTATS)
+	binary.NativeEndian.PutUint32(gstats[4:], nStats)
+
+	if err := ethtoolIoctl(fd, es.Interface, gstats); err != nil {
+		return err
+	}
+
+	es.Stats = parseEthtoolStats(gstrings, gstats, statsFilter)
+
+	return nil
+}
+
+// parseEthtoolStats pairs the names of an ETHTOOL_GSTRINGS reply with the values of an ETHTOOL_GSTATS reply
+func parseEthtoolStats(gstrings, gstats []byte, statsFilter *includeExcludeFilter) map[string]uint64 {
+	// the driver may return fewer than requested
+	n := binary.NativeEndian.Uint32(gstats[4:8])
+	if l := binary.NativeEndian.Uint32(gstrings[8:12]); l < n {
+		n = l
+	}
+
+	stats := make(map[string]uint64)
+
+	for i := 0; i < int(n); i++ {
+		name := unix.ByteSliceToString(gstrings[12+i*ethGStringLen : 12+(i+1)*ethGStringLen])
+		if name == "" || !statsFilter.match(name) {
+			continue
+		}
+
+		stats[name] = binary.NativeEndian.Uint64(gstats[8+i*8:])
+	}
+
+	return stats
+}
+
+// ethtoolIoctl runs SIOCETHTOOL with ifr_data pointing at data, a struct starting with the ethtool command
+//
+// unix.Ifreq has no setter for pointer data (ifreqData is unexported), the pointer is stored in the ifr_ifru union
+func ethtoolIoctl(fd int, iface string, data []byte) error {
+	ifr, err := unix.NewIfreq(iface)
+	if err != nil {
+		return err
+	}
+
+	*(*unsafe.Pointer)(unsafe.Add(unsafe.Pointer(ifr), unix.IFNAMSIZ)) = unsafe.Pointer(&data[0]) //nolint
+
+	err = unix.IoctlIfreq(fd, unix.SIOCETHTOOL, ifr)
+	runtime.KeepAlive(data)
+
+	return err
+}
@@ -0,0 +1,48 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestGetEthtoolStats(t *testing.T) {
+	stats, err := getEthtoolStats()
+	if err != nil {
+		t.Error(err)
+	}
+
+	for i := range stats {
+		if stats[i].Interface == "lo" && stats[i].MTU < 1 {
+			t.Errorf("expect lo to have an mtu: %+v", stats[i])
+		}
+	}
+}
+
+func TestParseEthtoolStats(t *testing.T) {
+	names := []string{"rx_missed_errors", "tx_packets", "rx_dropped"}
+
+	gstrings := make([]byte, 12+len(names)*ethGStringLen)
+	binary.NativeEndian.PutUint32(gstrings[8:], uint32(len(names)))
+
+	for i, name := range names {
+		copy(gstrings[12+i*ethGStringLen:], name)
+	}
+
+	// the driver returned one value less than it has names for
+	gstats := make([]byte, 8+len(names)*8)
+	binary.NativeEndian.PutUint32(gstats[4:], 2)
+	binary.NativeEndian.PutUint64(gstats[8:], 7)
+	binary.NativeEndian.PutUint64(gstats[16:], 100)
+	binary.NativeEndian.PutUint64(gstats[24:], 3)
+
+	filter, err := newIncludeExcludeFilter("(missed|drop)", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats := parseEthtoolStats(gstrings, gstats, filter)
+	if len(stats) != 1 || stats["rx_missed_errors"] != 7 {
+		t.Errorf("unexpected stats %v", stats)
+	}
+}
@@ -3,28 +3,37 @@ package metrics
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"syscall"
+	"time"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
 
 	"go.uber.org/zap"
 )
 
-// Mounts from /proc/mounts
+// Mounts from /proc/self/mountinfo
 type Mounts struct {
-	Device    string
-	Mount     string
-	Type      string
-	Opts      string
-	Dump      bool
-	BootCheck bool
+	Device     string
+	Mount      string
+	Type       string
+	Opts       string
+	MajorMinor string
+	Root       string
 }
 
 // FilesystemStats stats
 type FilesystemStats struct {
 	Device     string
 	Mount      string
+	Type       string
+	ReadOnly   bool
+	Error      bool // statfs failed or timed out, remaining fields are unset
 	BlockSize  int64
 	Inodes     uint64
 	InodesFree uint64
@@ -36,6 +45,12 @@ type FilesystemStats struct {
 	BytesUtil  float64
 }
 
+var (
+	// stuckMounts mounts with a statfs call that timed out and hasn't returned yet (hung nfs, etc)
+	stuckMounts   = make(map[string]struct{})
+	stuckMountsMu sync.Mutex
+)
+
 // getFilesystemUtil calls statfs syscall to get utilization
 func getFilesystemUtil() ([]*FilesystemStats, error) {
 	log := zap.L().Sugar()
@@ -45,13 +60,30 @@ func getFilesystemUtil() ([]*FilesystemStats, error) {
 		return nil, err
 	}
 
+	timeout := time.Duration(config.GetFilesystemStatfsTimeout()) * time.Second
+
 	var fs []*FilesystemStats
 
 	for i := range mounts {
-		df := syscall.Statfs_t{}
+		readOnly := false
+		for _, opt := range strings.Split(mounts[i].Opts, ",") {
+			if opt == "ro" {
+				readOnly = true
+			}
+		}
 
-		if err := syscall.Statfs(mounts[i].Mount, &df); err != nil {
+		df, err := statfsWithTimeout(mounts[i].Mount, timeout)
+		if err != nil {
 			log.Error(err)
+
+			fs = append(fs, &FilesystemStats{
+				Device:   mounts[i].Device,
+				Mount:    mounts[i].Mount,
+				Type:     mounts[i].Type,
+				ReadOnly: readOnly,
+				Error:    true,
+			})
+
 			continue
 		}
 
@@ -59,38 +91,157 @@ func getFilesystemUtil() ([]*FilesystemStats, error) {
 		freeBlocks := df.Bfree
 		bytesUsed := (uint64(df.Bsize) * totalBlocks) - (uint64(df.Bsize) * freeBlocks)
 
+		var inodesUtil, bytesUtil float64
+		if df.Files > 0 {
+			inodesUtil = ((float64(df.Files) - float64(df.Ffree)) / float64(df.Files)) * 100
+		}
+		if totalBlocks > 0 {
+			bytesUtil = float64(bytesUsed) / (float64(df.Bsize) * float64(totalBlocks)) * 100
+		}
+
 		fs = append(fs, &FilesystemStats{
 			Device:     mounts[i].Device,
 			Mount:      mounts[i].Mount,
+			Type:       mounts[i].Type,
+			ReadOnly:   readOnly,
 			BlockSize:  df.Bsize,
 			Inodes:     df.Files,
 			InodesFree: df.Ffree,
 			InodesUsed: df.Files - df.Ffree,
-			InodesUtil: ((float64(df.Files) - float64(df.Ffree)) / float64(df.Files)) * 100,
+			InodesUtil: inodesUtil,
 			BytesTotal: uint64(df.Bsize) * totalBlocks,
 			BytesFree:  uint64(df.Bsize) * freeBlocks,
 			BytesUsed:  bytesUsed,
-			BytesUtil:  float64(bytesUsed) / (float64(df.Bsize) * float64(totalBlocks)) * 100,
+			BytesUtil:  bytesUtil,
 		})
 	}
 
 	return fs, nil
 }
 
-// getMounts reads /proc/mounts
+// statfsWithTimeout calls statfs, giving up after timeout
+//
+// a hung statfs (unreachable nfs server, etc) can't be cancelled, the mount is skipped until the call returns
+func statfsWithTimeout(mount string, timeout time.Duration) (*syscall.Statfs_t, error) {
+	stuckMountsMu.Lock()
+	_, stuck := stuckMounts[mount]
+	stuckMountsMu.Unlock()
+
+	if stuck {
+		return nil, fmt.Errorf("%s: %w (still pending)", mount, ErrStatfsTimeout)
+	}
+
+	type result struct {
+		df  syscall.Statfs_t
+		err error
+	}
+
+	ch := make(chan result, 1)
+
+	go func() {
+		var r result
+		r.err = syscall.Statfs(mount, &r.df)
+
+		ch <- r
+
+		stuckMountsMu.Lock()
+		delete(stuckMounts, mount)
+		stuckMountsMu.Unlock()
+	}()
+
+	select {
+	case r := <-ch:
+		return &r.df, r.err
+	case <-time.After(timeout):
+		stuckMountsMu.Lock()
+		defer stuckMountsMu.Unlock()
+
+		// returned while waiting on the lock
+		select {
+		case r := <-ch:
+			return &r.df, r.err
+		default:
+		}
+
+		stuckMounts[mount] = struct{}{}
+
+		return nil, fmt.Errorf("%s: %w", mount, ErrStatfsTimeout)
+	}
+}
+
+// getMounts reads /proc/self/mountinfo, applying the file_system fstype and mount filters
+//
+// bind mounts (same major:minor) are only returned once
 func getMounts() ([]*Mounts, error) {
-	_, err := os.Stat("/proc/mounts")
+	_, err := os.Stat("/proc/self/mountinfo")
 	if err != nil {
 		return nil, err
 	}
 
-	mountsFD, err := os.Open("/proc/mounts")
+	mountsFD, err := os.Open("/proc/self/mountinfo")
 	if err != nil {
 		return nil, err
 	}
 	defer mountsFD.Close() //nolint
 
-	reader := bufio.NewReader(mountsFD)
+	allMounts, err := parseMountinfo(mountsFD)
+	if err != nil {
+		return nil, err
+	}
+
+	fsTypeFilter, err := newIncludeExcludeFilter(config.GetFilesystemFSTypeInclude(), config.GetFilesystemFSTypeExclude())
+	if err != nil {
+		return nil, err
+	}
+
+	mountFilter, err := newIncludeExcludeFilter(config.GetFilesystemMountInclude(), config.GetFilesystemMountExclude())
+	if err != nil {
+		return nil, err
+	}
+
+	var mounts []*Mounts
+
+	for i := range allMounts {
+		if !fsTypeFilter.match(allMounts[i].Type) || !mountFilter.match(allMounts[i].Mount) {
+			continue
+		}
+
+		mounts = append(mounts, allMounts[i])
+	}
+
+	return dedupMounts(mounts), nil
+}
+
+// dedupMounts keeps a single mount per major:minor, preferring the mount of the filesystem root over bind mounts of a subdirectory
+func dedupMounts(mounts []*Mounts) []*Mounts {
+	seen := make(map[string]int)
+
+	var deduped []*Mounts
+
+	for i := range mounts {
+		j, ok := seen[mounts[i].MajorMinor]
+		if !ok {
+			seen[mounts[i].MajorMinor] = len(deduped)
+			deduped = append(deduped, mounts[i])
+
+			continue
+		}
+
+		if deduped[j].Root != "/" && mounts[i].Root == "/" {
+			deduped[j] = mounts[i]
+		}
+	}
+
+	return deduped
+}
+
+// parseMountinfo parses /proc/self/mountinfo:
+//
+//	36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+//
+// https://www.kernel.org/doc/Documentation/filesystems/proc.txt (3.5)
+func parseMountinfo(r io.Reader) ([]*Mounts, error) {
+	reader := bufio.NewReader(r)
 
 	var mounts []*Mounts
 
@@ -99,44 +250,65 @@ func getMounts() ([]*Mounts, error) {
 		if err != nil && err != io.EOF {
 			return nil, err
 		}
-		if err == io.EOF {
-			break
-		}
 
 		splitted := strings.Fields(data)
-		if len(splitted) != 6 { //nolint
-			break
-		}
 
-		device := splitted[0]
-		mount := splitted[1]
-		typefs := splitted[2]
-		opts := splitted[3]
+		// optional fields are terminated by a single hyphen
+		sep := -1
+		for i := 6; i < len(splitted); i++ {
+			if splitted[i] == "-" {
+				sep = i
 
-		var dump, boot bool
-		if splitted[4] == "0" {
-			dump = false
-		} else if splitted[4] == "1" {
-			dump = true
+				break
+			}
 		}
 
-		if splitted[5] == "0" {
-			boot = false
-		} else if splitted[5] == "1" {
-			boot = true
-		}
+		if sep != -1 && len(splitted) >= sep+3 { //nolint
+			opts := splitted[5]
+
+			// per superblock options (ro, etc) are reported separately from per mount options
+			if len(splitted) > sep+3 { //nolint
+				opts = fmt.Sprintf("%s,%s", opts, splitted[sep+3])
+			}
 
-		if strings.HasPrefix(device, "/") {
 			mounts = append(mounts, &Mounts{
-				Device:    device,
-				Mount:     mount,
-				Type:      typefs,
-				Opts:      opts,
-				Dump:      dump,
-				BootCheck: boot,
+				Device:     unescapeMountinfo(splitted[sep+2]),
+				Mount:      unescapeMountinfo(splitted[4]),
+				Type:       splitted[sep+1],
+				Opts:       opts,
+				MajorMinor: splitted[2],
+				Root:       unescapeMountinfo(splitted[3]),
 			})
 		}
+
+		if err == io.EOF {
+			break
+		}
 	}
 
 	return mounts, nil
 }
+
+// unescapeMountinfo replaces the octal escapes (\040 for space, etc) used in mountinfo
+func unescapeMountinfo(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+4 <= len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+
+				continue
+			}
+		}
+
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
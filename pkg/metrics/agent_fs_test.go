@@ -2,6 +2,7 @@
 package metrics
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -18,3 +19,43 @@ func TestGetMounts(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestParseMountinfo(t *testing.T) {
+	mountinfo := `22 1 252:1 / / rw,relatime shared:1 - ext4 /dev/vda1 rw,errors=remount-ro
+23 22 0:21 / /proc rw,nosuid,nodev,noexec,relatime shared:12 - proc proc rw
+24 22 252:16 / /mnt/data ro,relatime shared:2 master:3 - xfs /dev/vdb ro,attr2,inode64
+25 22 252:1 /srv /var/lib/srv rw,relatime shared:1 - ext4 /dev/vda1 rw,errors=remount-ro
+26 22 252:32 / /mnt/my\040disk rw,relatime - ext4 /dev/vdc rw
+short line
+27 22 0:50 / /mnt/nfs rw,relatime - nfs4 10.0.0.1:/export rw,vers=4.2`
+
+	mounts, err := parseMountinfo(strings.NewReader(mountinfo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mounts) != 6 {
+		t.Fatalf("expected 6 mounts, got %d", len(mounts))
+	}
+
+	if mounts[2].Mount != "/mnt/data" || mounts[2].Type != "xfs" || mounts[2].Device != "/dev/vdb" || !strings.Contains(mounts[2].Opts, "attr2") {
+		t.Errorf("unexpected mount %+v", mounts[2])
+	}
+
+	if mounts[4].Mount != "/mnt/my disk" {
+		t.Errorf("expected unescaped mount point, got %q", mounts[4].Mount)
+	}
+
+	if mounts[5].Device != "10.0.0.1:/export" || mounts[5].Type != "nfs4" {
+		t.Errorf("unexpected mount after malformed line %+v", mounts[5])
+	}
+
+	deduped := dedupMounts([]*Mounts{mounts[3], mounts[0], mounts[2]})
+	if len(deduped) != 2 {
+		t.Fatalf("expected 2 mounts after dedup, got %d", len(deduped))
+	}
+
+	if deduped[0].Mount != "/" {
+		t.Errorf("expected bind mount to be replaced by filesystem root, got %q", deduped[0].Mount)
+	}
+}
@@ -0,0 +1,245 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	sysClassHwmonPath   = "/sys/class/hwmon"
+	sysClassThermalPath = "/sys/class/thermal"
+)
+
+// hwmonSensorRe matches hwmon sensor attributes (temp1_input, fan2_alarm, in0_input, power1_average)
+//
+// https://www.kernel.org/doc/Documentation/hwmon/sysfs-interface
+var hwmonSensorRe = regexp.MustCompile(`^(temp|fan|in|power)([0-9]+)_(input|average|crit|alarm)$`)
+
+// hwmonScale divisor to convert hwmon units (millidegree, millivolt, microwatt) to base units
+var hwmonScale = map[string]float64{
+	"temp":  1000,
+	"fan":   1,
+	"in":    1000,
+	"power": 1000000,
+}
+
+// HwmonSensor a single hwmon sensor (temp1, fan2, etc)
+type HwmonSensor struct {
+	Chip     string // device the chip is attached to (coretemp.0, 0000:00:18.3), hwmonN if there is none
+	ChipName string // driver name (coretemp, k10temp, nvme)
+	Sensor   string // temp1, fan2, in0, power1
+	Type     string // temp, fan, in, power
+	Label    string // <sensor>_label, the sensor name if not set
+
+	Value    float64 // celsius, rpm, volts, watts
+	HasValue bool
+	Crit     float64 // temp only
+	HasCrit  bool
+	Alarm    float64
+	HasAlarm bool
+}
+
+// ThermalZone a /sys/class/thermal/thermal_zone*
+type ThermalZone struct {
+	Zone string
+	Type string
+	Temp float64 // celsius
+}
+
+// CoolingDevice a /sys/class/thermal/cooling_device*
+type CoolingDevice struct {
+	Device   string
+	Type     string
+	CurState float64
+	MaxState float64
+}
+
+// getHwmonSensors reads every sensor under root (/sys/class/hwmon)
+//
+// returns an empty list if root doesn't exist, VMs usually don't expose any hwmon devices
+func getHwmonSensors(root string) ([]*HwmonSensor, error) {
+	log := zap.L().Sugar()
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var sensors []*HwmonSensor
+
+	for i := range entries {
+		dir := filepath.Join(root, entries[i].Name())
+
+		s, err := getHwmonChipSensors(dir)
+		if err != nil {
+			log.Debugf("hwmon: %s: %s", dir, err)
+
+			continue
+		}
+
+		sensors = append(sensors, s...)
+	}
+
+	return sensors, nil
+}
+
+// getHwmonChipSensors reads the sensors of a single hwmonN directory
+func getHwmonChipSensors(dir string) ([]*HwmonSensor, error) {
+	chip := filepath.Base(dir)
+	if device, err := filepath.EvalSymlinks(filepath.Join(dir, "device")); err == nil {
+		chip = filepath.Base(device)
+	}
+
+	// older drivers put the attributes in hwmonN/device rather than hwmonN
+	if _, err := os.Stat(filepath.Join(dir, "name")); err != nil {
+		dir = filepath.Join(dir, "device")
+	}
+
+	chipName, err := readSysString(filepath.Join(dir, "name"))
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	bySensor := make(map[string]*HwmonSensor)
+
+	for i := range files {
+		m := hwmonSensorRe.FindStringSubmatch(files[i].Name())
+		if m == nil {
+			continue
+		}
+
+		// reads fail with EIO/ENODATA for sensors the hardware doesn't populate
+		raw, err := readSysFloat(filepath.Join(dir, files[i].Name()))
+		if err != nil {
+			continue
+		}
+
+		sensor := m[1] + m[2]
+
+		s, ok := bySensor[sensor]
+		if !ok {
+			s = &HwmonSensor{
+				Chip:     chip,
+				ChipName: chipName,
+				Sensor:   sensor,
+				Type:     m[1],
+				Label:    sensor,
+			}
+
+			if label, err := readSysString(filepath.Join(dir, sensor+"_label")); err == nil && label != "" {
+				s.Label = label
+			}
+
+			bySensor[sensor] = s
+		}
+
+		switch m[3] {
+		case "input":
+			s.Value = raw / hwmonScale[m[1]]
+			s.HasValue = true
+		case "average":
+			// power meters usually only have average
+			if !s.HasValue {
+				s.Value = raw / hwmonScale[m[1]]
+				s.HasValue = true
+			}
+		case "crit":
+			if m[1] == "temp" {
+				s.Crit = raw / hwmonScale[m[1]]
+				s.HasCrit = true
+			}
+		case "alarm":
+			s.Alarm = raw
+			s.HasAlarm = true
+		}
+	}
+
+	sensors := make([]*HwmonSensor, 0, len(bySensor))
+	for _, s := range bySensor {
+		sensors = append(sensors, s)
+	}
+
+	sort.Slice(sensors, func(i, j int) bool { return sensors[i].Sensor < sensors[j].Sensor })
+
+	return sensors, nil
+}
+
+// getThermalZones reads /sys/class/thermal/thermal_zone* and cooling_device*
+func getThermalZones(root string) ([]*ThermalZone, []*CoolingDevice, error) {
+	log := zap.L().Sugar()
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+
+		return nil, nil, err
+	}
+
+	var zones []*ThermalZone
+
+	var devices []*CoolingDevice
+
+	for i := range entries {
+		name := entries[i].Name()
+		dir := filepath.Join(root, name)
+
+		typ, err := readSysString(filepath.Join(dir, "type"))
+		if err != nil {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(name, "thermal_zone"):
+			// disabled zones return EINVAL/ENODATA
+			temp, err := readSysFloat(filepath.Join(dir, "temp"))
+			if err != nil {
+				log.Debugf("thermal: %s: %s", dir, err)
+
+				continue
+			}
+
+			zones = append(zones, &ThermalZone{
+				Zone: strings.TrimPrefix(name, "thermal_zone"),
+				Type: typ,
+				Temp: temp / 1000, //nolint
+			})
+		case strings.HasPrefix(name, "cooling_device"):
+			cur, err := readSysFloat(filepath.Join(dir, "cur_state"))
+			if err != nil {
+				continue
+			}
+
+			maxState, err := readSysFloat(filepath.Join(dir, "max_state"))
+			if err != nil {
+				continue
+			}
+
+			devices = append(devices, &CoolingDevice{
+				Device:   strings.TrimPrefix(name, "cooling_device"),
+				Type:     typ,
+				CurState: cur,
+				MaxState: maxState,
+			})
+		}
+	}
+
+	return zones, devices, nil
+}
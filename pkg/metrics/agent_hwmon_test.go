@@ -0,0 +1,104 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeSysFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(path, []byte(content+"\n"), 0o644); err != nil { //nolint
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGetHwmonSensors(t *testing.T) {
+	root := t.TempDir()
+
+	writeSysFiles(t, root, map[string]string{
+		"hwmon0/name":              "coretemp",
+		"hwmon0/temp1_input":       "45000",
+		"hwmon0/temp1_crit":        "100000",
+		"hwmon0/temp1_label":       "Package id 0",
+		"hwmon0/temp2_input":       "43500",
+		"hwmon0/temp2_alarm":       "0",
+		"hwmon1/device/name":       "nct6775",
+		"hwmon1/device/fan1_input": "1250",
+		"hwmon1/device/fan1_alarm": "1",
+		"hwmon1/device/in0_input":  "1200",
+		"hwmon2/name":              "acpi_power_meter",
+		"hwmon2/power1_average":    "185000000",
+	})
+
+	sensors, err := getHwmonSensors(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]*HwmonSensor)
+	for i := range sensors {
+		got[sensors[i].ChipName+"/"+sensors[i].Sensor] = sensors[i]
+	}
+
+	if len(got) != 5 {
+		t.Fatalf("expected 5 sensors, got %d", len(got))
+	}
+
+	temp1 := got["coretemp/temp1"]
+	if temp1 == nil || temp1.Value != 45 || temp1.Crit != 100 || temp1.Label != "Package id 0" || temp1.Chip != "hwmon0" {
+		t.Errorf("unexpected temp1 %+v", temp1)
+	}
+
+	if temp2 := got["coretemp/temp2"]; temp2 == nil || temp2.Label != "temp2" || !temp2.HasAlarm || temp2.HasCrit {
+		t.Errorf("unexpected temp2 %+v", temp2)
+	}
+
+	if fan1 := got["nct6775/fan1"]; fan1 == nil || fan1.Value != 1250 || fan1.Alarm != 1 {
+		t.Errorf("unexpected fan1 %+v", fan1)
+	}
+
+	if in0 := got["nct6775/in0"]; in0 == nil || in0.Value != 1.2 {
+		t.Errorf("unexpected in0 %+v", in0)
+	}
+
+	if power1 := got["acpi_power_meter/power1"]; power1 == nil || power1.Value != 185 {
+		t.Errorf("unexpected power1 %+v", power1)
+	}
+}
+
+func TestGetThermalZones(t *testing.T) {
+	root := t.TempDir()
+
+	writeSysFiles(t, root, map[string]string{
+		"thermal_zone0/type":        "x86_pkg_temp",
+		"thermal_zone0/temp":        "52000",
+		"thermal_zone1/type":        "acpitz",
+		"cooling_device0/type":      "Processor",
+		"cooling_device0/cur_state": "2",
+		"cooling_device0/max_state": "10",
+	})
+
+	zones, devices, err := getThermalZones(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zones) != 1 || zones[0].Zone != "0" || zones[0].Type != "x86_pkg_temp" || zones[0].Temp != 52 {
+		t.Errorf("unexpected zones %+v", zones)
+	}
+
+	if len(devices) != 1 || devices[0].CurState != 2 || devices[0].MaxState != 10 {
+		t.Errorf("unexpected cooling devices %+v", devices)
+	}
+}
@@ -0,0 +1,304 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+)
+
+const (
+	devKmsgPath    = "/dev/kmsg"
+	kmsgBootIDPath = "/proc/sys/kernel/random/boot_id" // sequence numbers restart on boot
+
+	// a record is at most 1024 bytes of text plus the prefix and dictionary, the kernel rejects buffers that are too small with EINVAL
+	kmsgBufferSize = 8192
+)
+
+// KmsgRecord a single /dev/kmsg record
+//
+// https://docs.kernel.org/admin-guide/abi-testing.html#abi-dev-kmsg
+type KmsgRecord struct {
+	Priority  uint64 // facility << 3 | level
+	Seq       uint64
+	Timestamp uint64 // usec since boot
+	Message   string
+}
+
+// KmsgMatch counts of a kmsg.patterns entry, keyed by the first non empty capture group (process, device, interface)
+type KmsgMatch struct {
+	Pattern string
+	Match   string
+	Count   uint64
+}
+
+// kmsgPattern a compiled kmsg.patterns entry
+type kmsgPattern struct {
+	name string
+	re   *regexp.Regexp
+}
+
+// kmsgWatcher tails /dev/kmsg across gathers, every open file description has its own position in the ring buffer
+type kmsgWatcher struct {
+	path     string
+	fd       int
+	patterns []*kmsgPattern
+
+	stateFile  string // lastSeq of the previous v-agent run, empty disables
+	bootIDPath string
+	savedSeq   uint64
+
+	lastSeq uint64
+	seen    bool // lastSeq is set
+
+	records uint64
+	dropped uint64 // records overwritten before they were read
+	matches map[[2]string]uint64
+	order   [][2]string
+}
+
+var kmsg *kmsgWatcher
+
+func newKmsgWatcher(path, stateFile string, patterns []config.KmsgPattern) (*kmsgWatcher, error) {
+	w := kmsgWatcher{
+		path:       path,
+		fd:         -1,
+		stateFile:  stateFile,
+		bootIDPath: kmsgBootIDPath,
+		matches:    make(map[[2]string]uint64),
+	}
+
+	for i := range patterns {
+		re, err := regexp.Compile(patterns[i].Regex)
+		if err != nil {
+			return nil, err
+		}
+
+		w.patterns = append(w.patterns, &kmsgPattern{name: patterns[i].Name, re: re})
+	}
+
+	return &w, nil
+}
+
+// open opens path non blocking
+func (w *kmsgWatcher) open() error {
+	fd, err := syscall.Open(w.path, syscall.O_RDONLY|syscall.O_NONBLOCK|syscall.O_CLOEXEC, 0)
+	if err != nil {
+		return err
+	}
+
+	// the first open resumes after the sequence number saved by the previous run, without one (first run, no state
+	// file) the existing records are skipped so they aren't counted on every start. a reopen after an error starts
+	// from the oldest record and process skips up to lastSeq
+	if !w.seen && !w.loadState() {
+		if _, err := syscall.Seek(fd, 0, io.SeekEnd); err != nil {
+			syscall.Close(fd) //nolint
+
+			return err
+		}
+	}
+
+	w.fd = fd
+
+	return nil
+}
+
+// loadState restores lastSeq from stateFile, returns false if there is nothing to resume from
+//
+// a state file from a previous boot resumes from the oldest record, everything in the ring buffer was logged since
+func (w *kmsgWatcher) loadState() bool {
+	if w.stateFile == "" {
+		return false
+	}
+
+	data, err := os.ReadFile(w.stateFile)
+	if err != nil {
+		return false
+	}
+
+	bootID, seq, ok := strings.Cut(strings.TrimSpace(string(data)), " ")
+	if !ok {
+		return false
+	}
+
+	lastSeq, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	if current, err := readSysString(w.bootIDPath); err != nil || current != bootID {
+		return true
+	}
+
+	w.lastSeq = lastSeq
+	w.savedSeq = lastSeq
+	w.seen = true
+
+	return true
+}
+
+// saveState writes the boot id and lastSeq to stateFile if lastSeq changed since the last save
+func (w *kmsgWatcher) saveState() error {
+	if w.stateFile == "" || !w.seen || w.lastSeq == w.savedSeq {
+		return nil
+	}
+
+	bootID, err := readSysString(w.bootIDPath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(w.stateFile), 0o755); err != nil { //nolint
+		return err
+	}
+
+	// write and rename so a crash never leaves a truncated state file
+	tmp := w.stateFile + ".tmp"
+	if err := os.WriteFile(tmp, []byte(fmt.Sprintf("%s %d\n", bootID, w.lastSeq)), 0o644); err != nil { //nolint
+		return err
+	}
+
+	if err := os.Rename(tmp, w.stateFile); err != nil {
+		return err
+	}
+
+	w.savedSeq = w.lastSeq
+
+	return nil
+}
+
+// poll reads every record available since the last poll
+func (w *kmsgWatcher) poll() error {
+	if w.fd == -1 {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, kmsgBufferSize)
+
+	for {
+		n, err := syscall.Read(w.fd, buf)
+		if err != nil {
+			switch {
+			case errors.Is(err, syscall.EAGAIN):
+				return nil
+			case errors.Is(err, syscall.EPIPE):
+				// records were overwritten, the next read returns the oldest available one, counted as dropped by the seq gap
+				continue
+			case errors.Is(err, syscall.EINTR):
+				continue
+			}
+
+			syscall.Close(w.fd) //nolint
+			w.fd = -1
+
+			return err
+		}
+
+		if n == 0 {
+			return nil
+		}
+
+		rec, err := parseKmsgRecord(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		w.process(rec)
+	}
+}
+
+// process counts a record, records up to lastSeq were already counted before a reopen
+func (w *kmsgWatcher) process(rec *KmsgRecord) {
+	if w.seen {
+		if rec.Seq <= w.lastSeq {
+			return
+		}
+
+		if rec.Seq > w.lastSeq+1 {
+			w.dropped += rec.Seq - w.lastSeq - 1
+		}
+	}
+
+	w.lastSeq = rec.Seq
+	w.seen = true
+	w.records++
+
+	for _, p := range w.patterns {
+		m := p.re.FindStringSubmatch(rec.Message)
+		if m == nil {
+			continue
+		}
+
+		match := ""
+
+		for _, g := range m[1:] {
+			if g != "" {
+				match = g
+
+				break
+			}
+		}
+
+		key := [2]string{p.name, match}
+		if _, ok := w.matches[key]; !ok {
+			w.order = append(w.order, key)
+		}
+
+		w.matches[key]++
+	}
+}
+
+// stats returns the match counts since v-agent started
+func (w *kmsgWatcher) stats() []*KmsgMatch {
+	stats := make([]*KmsgMatch, 0, len(w.order))
+
+	for _, key := range w.order {
+		stats = append(stats, &KmsgMatch{Pattern: key[0], Match: key[1], Count: w.matches[key]})
+	}
+
+	return stats
+}
+
+// parseKmsgRecord parses a record, a comma separated prefix, the message and an optional dictionary of continuation lines:
+//
+//	6,339,5140900,-;NET: Registered protocol family 10
+//	 SUBSYSTEM=net
+//	 DEVICE=n2
+func parseKmsgRecord(b []byte) (*KmsgRecord, error) {
+	prefix, rest, ok := bytes.Cut(b, []byte(";"))
+	if !ok {
+		return nil, errors.New("malformed kmsg record, missing ;")
+	}
+
+	fields := bytes.SplitN(prefix, []byte(","), 5) //nolint
+	if len(fields) < 4 {                           //nolint
+		return nil, fmt.Errorf("malformed kmsg record prefix %q", prefix)
+	}
+
+	var rec KmsgRecord
+
+	for i, v := range []*uint64{&rec.Priority, &rec.Seq, &rec.Timestamp} {
+		n, err := strconv.ParseUint(string(fields[i]), 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		*v = n
+	}
+
+	msg, _, _ := bytes.Cut(rest, []byte("\n"))
+	rec.Message = string(msg)
+
+	return &rec, nil
+}
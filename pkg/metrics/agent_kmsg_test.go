@@ -0,0 +1,154 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+)
+
+func TestParseKmsgRecord(t *testing.T) {
+	rec, err := parseKmsgRecord([]byte("6,339,5140900,-;NET: Registered protocol family 10\n SUBSYSTEM=net\n DEVICE=n2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Priority != 6 || rec.Seq != 339 || rec.Timestamp != 5140900 || rec.Message != "NET: Registered protocol family 10" {
+		t.Errorf("unexpected record %+v", rec)
+	}
+
+	// fields added after flags are ignored
+	if rec, err := parseKmsgRecord([]byte("3,340,5140901,c,caller=T1;message\n")); err != nil || rec.Seq != 340 || rec.Message != "message" {
+		t.Errorf("unexpected record %+v %v", rec, err)
+	}
+
+	for _, b := range []string{"no separator\n", "6,339;short prefix\n", "6,x,5140900,-;bad seq\n"} {
+		if _, err := parseKmsgRecord([]byte(b)); err == nil {
+			t.Errorf("%q: expected error", b)
+		}
+	}
+}
+
+func TestKmsgWatcherProcess(t *testing.T) {
+	w, err := newKmsgWatcher(devKmsgPath, "", config.DefaultKmsgPatterns)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for seq, msg := range []string{
+		"Out of memory: Killed process 1234 (java) total-vm:8388608kB, anon-rss:4194304kB, file-rss:0kB, shmem-rss:0kB, UID:1000 pgtables:8400kB oom_score_adj:0",
+		"Memory cgroup out of memory: Killed process 4321 (java) total-vm:1024kB, anon-rss:512kB, file-rss:0kB, shmem-rss:0kB, UID:0 pgtables:40kB oom_score_adj:999",
+		"INFO: task kworker/u16:2:123 blocked for more than 120 seconds.",
+		"EXT4-fs error (device sda1): ext4_find_entry:1455: inode #2: comm ls: reading directory lblock 0",
+		"XFS (nvme0n1p1): Corruption detected. Unmount and run xfs_repair",
+		"blk_update_request: I/O error, dev sdb, sector 2048 op 0x0:(READ) flags 0x0 phys_seg 1 prio class 0",
+		"e1000e: eth0 NIC Link is Down",
+		"mlx5_core 0000:3b:00.0 ens1f0: Link down",
+		"mce: [Hardware Error]: Machine check events logged",
+		"NET: Registered protocol family 10",
+	} {
+		w.process(&KmsgRecord{Seq: uint64(seq), Message: msg})
+	}
+
+	expected := map[[2]string]uint64{
+		{"oom_kill", "java"}:           2,
+		{"hung_task", "kworker/u16:2"}: 1,
+		{"fs_error", "sda1"}:           1,
+		{"fs_error", "nvme0n1p1"}:      1,
+		{"io_error", "sdb"}:            1,
+		{"link_down", "eth0"}:          1,
+		{"link_down", "ens1f0"}:        1,
+		{"mce", ""}:                    1,
+	}
+
+	stats := w.stats()
+	if len(stats) != len(expected) {
+		t.Errorf("expected %d matches, got %d: %+v", len(expected), len(stats), w.matches)
+	}
+
+	for _, s := range stats {
+		if expected[[2]string{s.Pattern, s.Match}] != s.Count {
+			t.Errorf("%s %q: expected %d, got %d", s.Pattern, s.Match, expected[[2]string{s.Pattern, s.Match}], s.Count)
+		}
+	}
+
+	if w.records != 10 || w.dropped != 0 {
+		t.Errorf("expected 10 records and none dropped, got %d %d", w.records, w.dropped)
+	}
+
+	// records already counted before a reopen are skipped, gaps are dropped records
+	w.process(&KmsgRecord{Seq: 9, Message: "Out of memory: Killed process 1 (java)"})
+	w.process(&KmsgRecord{Seq: 15, Message: "Out of memory: Killed process 1 (java)"})
+
+	if w.records != 11 || w.dropped != 5 || w.matches[[2]string{"oom_kill", "java"}] != 3 {
+		t.Errorf("unexpected records %d dropped %d oom_kill %d", w.records, w.dropped, w.matches[[2]string{"oom_kill", "java"}])
+	}
+}
+
+func TestKmsgWatcherPollNotExist(t *testing.T) {
+	w, err := newKmsgWatcher(filepath.Join(t.TempDir(), "kmsg"), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.poll(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected os.ErrNotExist, got %v", err)
+	}
+
+	if _, err := newKmsgWatcher(devKmsgPath, "", []config.KmsgPattern{{Name: "invalid", Regex: "("}}); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestKmsgWatcherState(t *testing.T) {
+	dir := t.TempDir()
+
+	writeSysFiles(t, dir, map[string]string{"boot_id": "4d6d3b5c-1b5f-4a6e-9a2e-0a3f5c2d7e11\n"})
+
+	w, err := newKmsgWatcher(devKmsgPath, filepath.Join(dir, "state", "kmsg.state"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w.bootIDPath = filepath.Join(dir, "boot_id")
+
+	// first run, nothing to resume from
+	if w.loadState() {
+		t.Fatal("expected no state on the first run")
+	}
+
+	w.process(&KmsgRecord{Seq: 41})
+	w.process(&KmsgRecord{Seq: 42})
+
+	if err := w.saveState(); err != nil {
+		t.Fatal(err)
+	}
+
+	// restart, resumes after 42
+	w2, _ := newKmsgWatcher(devKmsgPath, w.stateFile, nil)
+	w2.bootIDPath = w.bootIDPath
+
+	if !w2.loadState() || !w2.seen || w2.lastSeq != 42 {
+		t.Fatalf("expected to resume after 42, got %d %v", w2.lastSeq, w2.seen)
+	}
+
+	w2.process(&KmsgRecord{Seq: 42})
+	w2.process(&KmsgRecord{Seq: 45})
+
+	if w2.records != 1 || w2.dropped != 2 {
+		t.Errorf("expected 1 record and 2 dropped, got %d %d", w2.records, w2.dropped)
+	}
+
+	// reboot, sequence numbers restart and every record in the ring buffer is read
+	writeSysFiles(t, dir, map[string]string{"boot_id": "8f1c2e0a-7d3b-4c5e-b6a1-2f9e8d7c6b5a\n"})
+
+	w3, _ := newKmsgWatcher(devKmsgPath, w.stateFile, nil)
+	w3.bootIDPath = w.bootIDPath
+
+	if !w3.loadState() || w3.seen {
+		t.Errorf("expected to read from the oldest record after a reboot, got seen %v", w3.seen)
+	}
+}
@@ -0,0 +1,212 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+const procSysPath = "/proc/sys"
+
+// KernelLimits system wide kernel tables and their limits from /proc/sys
+//
+// https://docs.kernel.org/admin-guide/sysctl/fs.html
+type KernelLimits struct {
+	FileAllocated float64
+	FileMax       float64
+	InodeTotal    float64
+	InodeFree     float64
+
+	EntropyAvail    float64
+	EntropyPoolSize float64
+
+	PIDMax     float64
+	ThreadsMax float64
+	Threads    float64
+
+	InotifyMaxUserWatches   float64
+	InotifyMaxUserInstances float64
+}
+
+// InotifyUsage inotify instances and watches of a user, limited per user by fs.inotify.max_user_{instances,watches}
+type InotifyUsage struct {
+	UID       string
+	Instances uint64
+	Watches   uint64
+}
+
+// getKernelLimits reads the limits under root (/proc/sys), threads are the scheduling entities from /proc/loadavg
+func getKernelLimits(root string) (*KernelLimits, error) {
+	var kl KernelLimits
+
+	// allocated, unused (always 0 since 2.6) and max
+	fileNr, err := readSysFields(filepath.Join(root, "fs", "file-nr"), 3) //nolint
+	if err != nil {
+		return nil, err
+	}
+
+	kl.FileAllocated = fileNr[0]
+	kl.FileMax = fileNr[2]
+
+	inodeNr, err := readSysFields(filepath.Join(root, "fs", "inode-nr"), 2) //nolint
+	if err != nil {
+		return nil, err
+	}
+
+	kl.InodeTotal = inodeNr[0]
+	kl.InodeFree = inodeNr[1]
+
+	for _, f := range []struct {
+		path  string
+		value *float64
+	}{
+		{filepath.Join(root, "kernel", "random", "entropy_avail"), &kl.EntropyAvail},
+		{filepath.Join(root, "kernel", "random", "poolsize"), &kl.EntropyPoolSize},
+		{filepath.Join(root, "kernel", "pid_max"), &kl.PIDMax},
+		{filepath.Join(root, "kernel", "threads-max"), &kl.ThreadsMax},
+		{filepath.Join(root, "fs", "inotify", "max_user_watches"), &kl.InotifyMaxUserWatches},
+		{filepath.Join(root, "fs", "inotify", "max_user_instances"), &kl.InotifyMaxUserInstances},
+	} {
+		v, err := readSysFloat(f.path)
+		if err != nil {
+			return nil, err
+		}
+
+		*f.value = v
+	}
+
+	loadavg, err := getLoadavg()
+	if err != nil {
+		return nil, err
+	}
+
+	kl.Threads = float64(loadavg.TasksTotal)
+
+	return &kl, nil
+}
+
+// readSysFields reads a whitespace separated line of at least n numbers (file-nr, inode-nr)
+func readSysFields(path string, n int) ([]float64, error) {
+	s, err := readSysString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) < n {
+		return nil, fmt.Errorf("%s: expected %d fields, got %d", path, n, len(fields))
+	}
+
+	values := make([]float64, n)
+
+	for i := range values {
+		if values[i], err = strconv.ParseFloat(fields[i], 64); err != nil {
+			return nil, err
+		}
+	}
+
+	return values, nil
+}
+
+// getInotifyUsage counts inotify instances and watches per uid by walking the fds of every process under root (/proc)
+//
+// requires CAP_SYS_PTRACE (or root) to see the fds of other users' processes
+func getInotifyUsage(root string) ([]*InotifyUsage, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string]*InotifyUsage)
+
+	var uids []string
+
+	for i := range entries {
+		if _, err := strconv.ParseUint(entries[i].Name(), 10, 64); err != nil {
+			continue
+		}
+
+		dir := filepath.Join(root, entries[i].Name())
+
+		// processes can exit while being read, skip them
+		fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+		if err != nil {
+			continue
+		}
+
+		var u *InotifyUsage
+
+		for j := range fds {
+			target, err := os.Readlink(filepath.Join(dir, "fd", fds[j].Name()))
+			if err != nil || target != "anon_inode:inotify" {
+				continue
+			}
+
+			if u == nil {
+				uid := processUID(dir)
+
+				if u = usage[uid]; u == nil {
+					u = &InotifyUsage{UID: uid}
+					usage[uid] = u
+					uids = append(uids, uid)
+				}
+			}
+
+			u.Instances++
+			u.Watches += countInotifyWatches(filepath.Join(dir, "fdinfo", fds[j].Name()))
+		}
+	}
+
+	stats := make([]*InotifyUsage, 0, len(uids))
+	for _, uid := range uids {
+		stats = append(stats, usage[uid])
+	}
+
+	return stats, nil
+}
+
+// processUID returns the owner of /proc/[pid], the effective uid of the process
+func processUID(dir string) string {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return "unknown"
+	}
+
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "unknown"
+	}
+
+	return strconv.FormatUint(uint64(st.Uid), 10)
+}
+
+// countInotifyWatches counts the watches listed in an inotify fdinfo:
+//
+//	pos:	0
+//	flags:	02004000
+//	mnt_id:	15
+//	ino:	1057
+//	inotify wd:1 ino:1b1f sdev:800002 mask:fc6 ignored_mask:0 fhandle-bytes:8 fhandle-type:1 f_handle:1f1b0000
+func countInotifyWatches(path string) uint64 {
+	data, err := os.ReadFile(path) //nolint
+	if err != nil {
+		return 0
+	}
+
+	var watches uint64
+
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		if strings.HasPrefix(sc.Text(), "inotify wd:") {
+			watches++
+		}
+	}
+
+	return watches
+}
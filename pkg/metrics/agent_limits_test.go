@@ -0,0 +1,102 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestGetKernelLimits(t *testing.T) {
+	dir := t.TempDir()
+
+	writeSysFiles(t, dir, map[string]string{
+		"fs/file-nr":                    "3264\t0\t9223372036854775807\n",
+		"fs/inode-nr":                   "120511\t6427\n",
+		"fs/inotify/max_user_watches":   "8192\n",
+		"fs/inotify/max_user_instances": "128\n",
+		"kernel/random/entropy_avail":   "256\n",
+		"kernel/random/poolsize":        "256\n",
+		"kernel/pid_max":                "4194304\n",
+		"kernel/threads-max":            "63371\n",
+	})
+
+	kl, err := getKernelLimits(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kl.FileAllocated != 3264 || kl.FileMax != 9223372036854775807 || kl.InodeTotal != 120511 || kl.InodeFree != 6427 {
+		t.Errorf("unexpected file/inode limits %+v", kl)
+	}
+
+	if kl.EntropyAvail != 256 || kl.EntropyPoolSize != 256 || kl.PIDMax != 4194304 || kl.ThreadsMax != 63371 {
+		t.Errorf("unexpected kernel limits %+v", kl)
+	}
+
+	if kl.InotifyMaxUserWatches != 8192 || kl.InotifyMaxUserInstances != 128 {
+		t.Errorf("unexpected inotify limits %+v", kl)
+	}
+
+	if kl.Threads == 0 {
+		t.Error("expected threads from /proc/loadavg")
+	}
+
+	writeSysFiles(t, dir, map[string]string{"fs/file-nr": "3264\n"})
+
+	if _, err := getKernelLimits(dir); err == nil {
+		t.Error("expected error for malformed file-nr")
+	}
+}
+
+func TestGetInotifyUsage(t *testing.T) {
+	dir := t.TempDir()
+
+	fdinfo := "pos:\t0\nflags:\t02004000\nmnt_id:\t15\nino:\t1057\n" +
+		"inotify wd:2 ino:1b1f sdev:800002 mask:fc6 ignored_mask:0 fhandle-bytes:8 fhandle-type:1 f_handle:1f1b0000\n" +
+		"inotify wd:1 ino:2 sdev:800002 mask:fc6 ignored_mask:0 fhandle-bytes:8 fhandle-type:1 f_handle:02000000\n"
+
+	writeSysFiles(t, dir, map[string]string{
+		"100/fdinfo/3":  fdinfo,
+		"100/fdinfo/5":  "pos:\t0\nflags:\t02004000\n",
+		"200/fdinfo/4":  fdinfo,
+		"self/fdinfo/3": fdinfo,
+	})
+
+	for _, link := range []struct {
+		path   string
+		target string
+	}{
+		{"100/fd/0", "/dev/null"},
+		{"100/fd/3", "anon_inode:inotify"},
+		{"100/fd/5", "anon_inode:inotify"},
+		{"200/fd/4", "anon_inode:inotify"},
+		{"300/fd/1", "socket:[1234]"},
+		{"self/fd/3", "anon_inode:inotify"},
+	} {
+		path := filepath.Join(dir, link.path)
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Symlink(link.target, path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := getInotifyUsage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// every fixture is owned by the user running the test
+	if len(usage) != 1 {
+		t.Fatalf("expected 1 user, got %d", len(usage))
+	}
+
+	if usage[0].UID != strconv.Itoa(os.Getuid()) || usage[0].Instances != 3 || usage[0].Watches != 4 {
+		t.Errorf("unexpected inotify usage %+v", usage[0])
+	}
+}
@@ -0,0 +1,194 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	procMDStatPath = "/proc/mdstat"
+	sysBlockPath   = "/sys/block"
+)
+
+var (
+	mdStatusRe   = regexp.MustCompile(`\[([0-9]+)/([0-9]+)\]`)
+	mdProgressRe = regexp.MustCompile(`(resync|recovery|check|repair|reshape)\s*=\s*([0-9.]+)%`)
+	mdPendingRe  = regexp.MustCompile(`(resync|recovery|check|repair|reshape)\s*=\s*(DELAYED|PENDING)`)
+	mdFinishRe   = regexp.MustCompile(`finish=([0-9.]+)min`)
+	mdSpeedRe    = regexp.MustCompile(`speed=([0-9]+)K/sec`)
+)
+
+// MDStats a software raid (md) array
+//
+// https://docs.kernel.org/admin-guide/md.html
+type MDStats struct {
+	Device     string
+	State      string // array_state (clean, active, readonly, etc), active/inactive from /proc/mdstat if sysfs is unavailable
+	Level      string // raid1, raid5, etc, empty for inactive arrays
+	Disks      uint64 // disks the array should have
+	Active     uint64
+	Failed     uint64
+	Spare      uint64
+	Degraded   uint64 // missing disks
+	SyncAction string // idle, resync, recover, check, repair, reshape
+	SyncPct    float64
+	SyncFinish float64 // seconds
+	SyncSpeed  float64 // bytes/s
+}
+
+// getMDStats reads /proc/mdstat and /sys/block/md*/md
+//
+// returns an empty list if the md driver isn't loaded
+func getMDStats(mdstatPath, sysBlock string) ([]*MDStats, error) {
+	fd, err := os.Open(mdstatPath) //nolint
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer fd.Close() //nolint
+
+	arrays, err := parseMDStat(fd)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range arrays {
+		addMDSysfs(arrays[i], filepath.Join(sysBlock, arrays[i].Device, "md"))
+	}
+
+	return arrays, nil
+}
+
+// addMDSysfs fills in state from /sys/block/<md>/md, which is more precise than /proc/mdstat
+func addMDSysfs(md *MDStats, dir string) {
+	if state, err := readSysString(filepath.Join(dir, "array_state")); err == nil {
+		md.State = state
+	}
+
+	if degraded, err := readSysFloat(filepath.Join(dir, "degraded")); err == nil {
+		md.Degraded = uint64(degraded)
+	}
+
+	if action, err := readSysString(filepath.Join(dir, "sync_action")); err == nil {
+		md.SyncAction = action
+	}
+}
+
+// parseMDStat parses /proc/mdstat:
+//
+//	md0 : active raid5 sdd1[3](S) sdc1[2] sdb1[1](F) sda1[0]
+//	      1953260544 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/2] [U_U]
+//	      [=>...................]  recovery =  8.5% (83263872/976630336) finish=89.3min speed=166648K/sec
+func parseMDStat(r io.Reader) ([]*MDStats, error) {
+	sc := bufio.NewScanner(r)
+
+	var arrays []*MDStats
+
+	var md *MDStats
+
+	for sc.Scan() {
+		line := sc.Text()
+		splitted := strings.Fields(line)
+
+		if len(splitted) >= 3 && strings.HasPrefix(splitted[0], "md") && splitted[1] == ":" { //nolint
+			md = &MDStats{
+				Device:     splitted[0],
+				State:      splitted[2],
+				SyncAction: "idle",
+				SyncPct:    100, //nolint
+			}
+
+			arrays = append(arrays, md)
+
+			for _, f := range splitted[3:] {
+				switch {
+				case strings.HasPrefix(f, "("):
+					// (auto-read-only), (read-only)
+				case !strings.Contains(f, "["):
+					md.Level = f
+				case strings.HasSuffix(f, "(F)"):
+					md.Failed++
+				case strings.HasSuffix(f, "(S)"):
+					md.Spare++
+				default:
+					md.Active++
+				}
+			}
+
+			// raid0/linear have no [n/m] status, every member is required
+			md.Disks = md.Active + md.Failed
+
+			continue
+		}
+
+		if md == nil || len(splitted) == 0 {
+			md = nil
+
+			continue
+		}
+
+		if m := mdStatusRe.FindStringSubmatch(line); m != nil {
+			disks, err := strconv.ParseUint(m[1], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			active, err := strconv.ParseUint(m[2], 10, 64)
+			if err != nil {
+				return nil, err
+			}
+
+			md.Disks = disks
+			md.Active = active
+			md.Degraded = disks - active
+		}
+
+		if m := mdProgressRe.FindStringSubmatch(line); m != nil {
+			pct, err := strconv.ParseFloat(m[2], 64)
+			if err != nil {
+				return nil, err
+			}
+
+			md.SyncAction = normalizeMDSyncAction(m[1])
+			md.SyncPct = pct
+
+			if f := mdFinishRe.FindStringSubmatch(line); f != nil {
+				finish, _ := strconv.ParseFloat(f[1], 64)
+				md.SyncFinish = finish * 60 //nolint
+			}
+
+			if s := mdSpeedRe.FindStringSubmatch(line); s != nil {
+				speed, _ := strconv.ParseFloat(s[1], 64)
+				md.SyncSpeed = speed * 1024 //nolint
+			}
+		} else if m := mdPendingRe.FindStringSubmatch(line); m != nil {
+			md.SyncAction = normalizeMDSyncAction(m[1])
+			md.SyncPct = 0
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return arrays, nil
+}
+
+// normalizeMDSyncAction maps /proc/mdstat progress names to the sync_action names in sysfs
+func normalizeMDSyncAction(action string) string {
+	if action == "recovery" {
+		return "recover"
+	}
+
+	return action
+}
@@ -0,0 +1,89 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testMDStat = `Personalities : [raid1] [raid6] [raid5] [raid4] [raid0]
+md127 : active raid1 sdb1[1] sda1[0]
+      1953382464 blocks super 1.2 [2/2] [UU]
+      bitmap: 0/15 pages [0KB], 65536KB chunk
+
+md0 : active raid5 sdd1[3](S) sdc1[2] sdb1[1](F) sda1[0]
+      1953260544 blocks super 1.2 level 5, 512k chunk, algorithm 2 [3/2] [U_U]
+      [=>...................]  recovery =  8.5% (83263872/976630336) finish=89.3min speed=166648K/sec
+
+md2 : active (auto-read-only) raid1 sdf1[1] sde1[0]
+      976630336 blocks super 1.2 [2/2] [UU]
+        resync=PENDING
+
+md3 : active raid0 sdh1[1] sdg1[0]
+      1953260544 blocks super 1.2 512k chunks
+
+md1 : inactive sdi1[0](S)
+      976630336 blocks super 1.2
+
+unused devices: <none>
+`
+
+func TestParseMDStat(t *testing.T) {
+	arrays, err := parseMDStat(strings.NewReader(testMDStat))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(arrays) != 5 {
+		t.Fatalf("expected 5 arrays, got %d", len(arrays))
+	}
+
+	for i, expected := range []MDStats{
+		{Device: "md127", State: "active", Level: "raid1", Disks: 2, Active: 2, SyncAction: "idle", SyncPct: 100},
+		{Device: "md0", State: "active", Level: "raid5", Disks: 3, Active: 2, Failed: 1, Spare: 1, Degraded: 1, SyncAction: "recover", SyncPct: 8.5, SyncFinish: 5358, SyncSpeed: 166648 * 1024},
+		{Device: "md2", State: "active", Level: "raid1", Disks: 2, Active: 2, SyncAction: "resync"},
+		{Device: "md3", State: "active", Level: "raid0", Disks: 2, Active: 2, SyncAction: "idle", SyncPct: 100},
+		{Device: "md1", State: "inactive", Spare: 1, SyncAction: "idle", SyncPct: 100},
+	} {
+		got := *arrays[i]
+
+		// float rounding of finish=89.3min
+		if d := got.SyncFinish - expected.SyncFinish; d > 0.001 || d < -0.001 {
+			t.Errorf("%s: expected finish %f, got %f", expected.Device, expected.SyncFinish, got.SyncFinish)
+		}
+
+		got.SyncFinish = expected.SyncFinish
+
+		if got != expected {
+			t.Errorf("expected %+v, got %+v", expected, got)
+		}
+	}
+}
+
+func TestGetMDStats(t *testing.T) {
+	root := t.TempDir()
+
+	writeSysFiles(t, root, map[string]string{
+		"mdstat":                     testMDStat,
+		"block/md0/md/array_state":   "clean",
+		"block/md0/md/degraded":      "1",
+		"block/md0/md/sync_action":   "recover",
+		"block/md127/md/array_state": "active-idle",
+	})
+
+	arrays, err := getMDStats(filepath.Join(root, "mdstat"), filepath.Join(root, "block"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if arrays[0].State != "active-idle" || arrays[1].State != "clean" || arrays[1].Degraded != 1 {
+		t.Errorf("expected sysfs state, got %+v %+v", arrays[0], arrays[1])
+	}
+
+	// md not loaded
+	arrays, err = getMDStats(filepath.Join(root, "missing"), filepath.Join(root, "block"))
+	if err != nil || len(arrays) != 0 {
+		t.Errorf("expected no arrays and no error, got %d %v", len(arrays), err)
+	}
+}
@@ -11,12 +11,23 @@ import (
 
 // MemInfo is container for memory metrics
 type MemInfo struct {
-	MemTotal  uint64
-	MemFree   uint64
-	Buffers   uint64
-	Cached    uint64
-	SwapTotal uint64
-	SwapFree  uint64
+	MemTotal     uint64
+	MemFree      uint64
+	MemAvailable uint64
+	Buffers      uint64
+	Cached       uint64
+	SwapTotal    uint64
+	SwapFree     uint64
+
+	// Fields contains every field from /proc/meminfo, keyed by the field name as the kernel reports it
+	Fields map[string]MemInfoField
+}
+
+// MemInfoField a single field from /proc/meminfo
+type MemInfoField struct {
+	Value uint64
+	// Bytes is false for fields that are counts (HugePages_Total, etc) rather than sizes
+	Bytes bool
 }
 
 // getMeminfo reads /proc/meminfo and calculates the systems current memory
@@ -33,9 +44,16 @@ func getMeminfo() (*MemInfo, error) {
 	}
 	defer procMemInfoFD.Close() //nolint
 
-	reader := bufio.NewReader(procMemInfoFD)
+	return parseMeminfo(procMemInfoFD)
+}
 
-	var meminfo MemInfo
+// parseMeminfo parses the contents of /proc/meminfo
+func parseMeminfo(r io.Reader) (*MemInfo, error) {
+	reader := bufio.NewReader(r)
+
+	meminfo := MemInfo{
+		Fields: make(map[string]MemInfoField),
+	}
 
 	for {
 		data, err := reader.ReadString('\n')
@@ -47,62 +65,44 @@ func getMeminfo() (*MemInfo, error) {
 			break
 		}
 
+		// Name: value [kB]
 		splitted := strings.Fields(data)
+		if len(splitted) < 2 { //nolint
+			continue
+		}
 
-		for _ = range splitted { //nolint
-			if splitted[0] == "MemTotal:" {
-				val, err1 := strconv.ParseUint(splitted[1], 10, 64)
-
-				if err1 != nil {
-					return nil, err1
-				}
-
-				meminfo.MemTotal = val * 1024
-			} else if splitted[0] == "Buffers:" {
-				val, err1 := strconv.ParseUint(splitted[1], 10, 64)
-
-				if err1 != nil {
-					return nil, err1
-				}
-
-				meminfo.Buffers = val * 1024
-			} else if splitted[0] == "Cached:" {
-				val, err1 := strconv.ParseUint(splitted[1], 10, 64)
-
-				if err1 != nil {
-					return nil, err1
-				}
-
-				meminfo.Cached = val * 1024
-			} else if splitted[0] == "MemFree:" {
-				val, err1 := strconv.ParseUint(splitted[1], 10, 64)
+		val, err1 := strconv.ParseUint(splitted[1], 10, 64)
+		if err1 != nil {
+			return nil, err1
+		}
 
-				if err1 != nil {
-					return nil, err1
-				}
+		field := MemInfoField{
+			Value: val,
+		}
 
-				meminfo.MemFree = val * 1024
-			} else if splitted[0] == "SwapTotal:" {
-				val, err1 := strconv.ParseUint(splitted[1], 10, 64)
+		if len(splitted) == 3 && splitted[2] == "kB" { //nolint
+			field.Value = val * 1024
+			field.Bytes = true
+		}
 
-				if err1 != nil {
-					return nil, err1
-				}
+		meminfo.Fields[strings.TrimSuffix(splitted[0], ":")] = field
+	}
 
-				meminfo.SwapTotal = val * 1024
-			} else if splitted[0] == "SwapFree:" {
-				val, err1 := strconv.ParseUint(splitted[1], 10, 64)
+	meminfo.MemTotal = meminfo.Fields["MemTotal"].Value
+	meminfo.MemFree = meminfo.Fields["MemFree"].Value
+	meminfo.Buffers = meminfo.Fields["Buffers"].Value
+	meminfo.Cached = meminfo.Fields["Cached"].Value
+	meminfo.SwapTotal = meminfo.Fields["SwapTotal"].Value
+	meminfo.SwapFree = meminfo.Fields["SwapFree"].Value
 
-				if err1 != nil {
-					return nil, err1
-				}
+	meminfo.MemFree = meminfo.MemFree + meminfo.Buffers + meminfo.Cached
 
-				meminfo.SwapFree = val * 1024
-			}
-		}
+	// MemAvailable was added in 3.14, fall back to free + buffers + cached
+	if v, ok := meminfo.Fields["MemAvailable"]; ok {
+		meminfo.MemAvailable = v.Value
+	} else {
+		meminfo.MemAvailable = meminfo.MemFree
 	}
 
-	meminfo.MemFree = meminfo.MemFree + meminfo.Buffers + meminfo.Cached
-
 	return &meminfo, nil
 }
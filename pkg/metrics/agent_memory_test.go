@@ -2,6 +2,7 @@
 package metrics
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -11,3 +12,53 @@ func TestGetMeminfo(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestParseMeminfo(t *testing.T) {
+	data := `MemTotal:       16318996 kB
+MemFree:         1206424 kB
+MemAvailable:   10170688 kB
+Buffers:          736052 kB
+Cached:          8226332 kB
+Active(anon):    4097772 kB
+HugePages_Total:       2
+Hugepagesize:       2048 kB
+`
+
+	m, err := parseMeminfo(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.MemTotal != 16318996*1024 {
+		t.Errorf("unexpected MemTotal %d", m.MemTotal)
+	}
+
+	if m.MemAvailable != 10170688*1024 {
+		t.Errorf("unexpected MemAvailable %d", m.MemAvailable)
+	}
+
+	if m.MemFree != (1206424+736052+8226332)*1024 {
+		t.Errorf("unexpected MemFree %d", m.MemFree)
+	}
+
+	if f := m.Fields["Active(anon)"]; !f.Bytes || f.Value != 4097772*1024 {
+		t.Errorf("unexpected Active(anon) %+v", f)
+	}
+
+	if f := m.Fields["HugePages_Total"]; f.Bytes || f.Value != 2 {
+		t.Errorf("unexpected HugePages_Total %+v", f)
+	}
+}
+
+func TestSanitizeMetricName(t *testing.T) {
+	for in, out := range map[string]string{
+		"Active(anon)":          "active_anon",
+		"HugePages_Total":       "hugepages_total",
+		"MemAvailable":          "memavailable",
+		"TCPExt:SyncookiesSent": "tcpext_syncookiessent",
+	} {
+		if got := sanitizeMetricName(in); got != out {
+			t.Errorf("sanitizeMetricName(%q) = %q, expect %q", in, got, out)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+)
+
+// snmp6Protos prefixes used in /proc/net/snmp6, the file has no header to split the protocol from the field
+var snmp6Protos = []string{"Ip6", "Icmp6", "UdpLite6", "Udp6"}
+
+// getNetStats reads /proc/net/snmp, /proc/net/netstat and /proc/net/snmp6
+//
+// keys are <Proto>_<Field> (Tcp_RetransSegs, TcpExt_ListenOverflows, Ip6_InReceives), only keys matching netstat.fields are returned
+func getNetStats() (map[string]float64, error) {
+	re, err := regexp.Compile(config.GetNetStatFields())
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]float64)
+
+	for _, path := range []string{"/proc/net/snmp", "/proc/net/netstat"} {
+		fd, err := os.Open(path) //nolint
+		if err != nil {
+			return nil, err
+		}
+
+		s, err := parseNetStat(fd)
+		fd.Close() //nolint
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		for k, v := range s {
+			stats[k] = v
+		}
+	}
+
+	// not present if ipv6 is disabled
+	fd, err := os.Open("/proc/net/snmp6")
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	} else if err == nil {
+		s, err := parseSNMP6(fd)
+		fd.Close() //nolint
+		if err != nil {
+			return nil, fmt.Errorf("/proc/net/snmp6: %w", err)
+		}
+
+		for k, v := range s {
+			stats[k] = v
+		}
+	}
+
+	filterNetStats(stats, re)
+
+	return stats, nil
+}
+
+// filterNetStats removes keys not matching netstat.fields
+func filterNetStats(stats map[string]float64, re *regexp.Regexp) {
+	for k := range stats {
+		if !re.MatchString(k) {
+			delete(stats, k)
+		}
+	}
+}
+
+// parseNetStat parses /proc/net/snmp and /proc/net/netstat, both are pairs of header and value lines:
+//
+//	Tcp: RtoAlgorithm RtoMin RtoMax ...
+//	Tcp: 1 200 120000 ...
+func parseNetStat(r io.Reader) (map[string]float64, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1024*1024) //nolint
+
+	stats := make(map[string]float64)
+
+	for sc.Scan() {
+		header := strings.Fields(sc.Text())
+
+		if !sc.Scan() {
+			return nil, fmt.Errorf("missing values for %q", strings.Join(header, " "))
+		}
+
+		values := strings.Fields(sc.Text())
+
+		if len(header) != len(values) || len(header) < 1 || header[0] != values[0] {
+			return nil, fmt.Errorf("mismatched header and values for %q", strings.Join(header, " "))
+		}
+
+		proto := strings.TrimSuffix(header[0], ":")
+
+		for i := 1; i < len(header); i++ {
+			// Tcp: MaxConn is -1
+			val, err := strconv.ParseFloat(values[i], 64)
+			if err != nil {
+				return nil, err
+			}
+
+			stats[fmt.Sprintf("%s_%s", proto, header[i])] = val
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// parseSNMP6 parses /proc/net/snmp6 (Ip6InReceives 3), keys are converted to Ip6_InReceives
+func parseSNMP6(r io.Reader) (map[string]float64, error) {
+	sc := bufio.NewScanner(r)
+
+	stats := make(map[string]float64)
+
+	for sc.Scan() {
+		splitted := strings.Fields(sc.Text())
+		if len(splitted) != 2 { //nolint
+			continue
+		}
+
+		val, err := strconv.ParseFloat(splitted[1], 64)
+		if err != nil {
+			return nil, err
+		}
+
+		key := splitted[0]
+		for _, proto := range snmp6Protos {
+			if strings.HasPrefix(key, proto) {
+				key = fmt.Sprintf("%s_%s", proto, strings.TrimPrefix(key, proto))
+
+				break
+			}
+		}
+
+		stats[key] = val
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// getSockStats reads /proc/net/sockstat and /proc/net/sockstat6
+//
+// keys are <Proto>_<field> (TCP_inuse, TCP_tw, sockets_used, TCP6_inuse), mem values are in pages, only keys matching
+// netstat.fields are returned
+func getSockStats() (map[string]float64, error) {
+	re, err := regexp.Compile(config.GetNetStatFields())
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]float64)
+
+	for _, path := range []string{"/proc/net/sockstat", "/proc/net/sockstat6"} {
+		fd, err := os.Open(path) //nolint
+		if err != nil {
+			// sockstat6 is not present if ipv6 is disabled
+			if errors.Is(err, os.ErrNotExist) && path == "/proc/net/sockstat6" {
+				continue
+			}
+
+			return nil, err
+		}
+
+		s, err := parseSockStat(fd)
+		fd.Close() //nolint
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		for k, v := range s {
+			stats[k] = v
+		}
+	}
+
+	filterNetStats(stats, re)
+
+	return stats, nil
+}
+
+// parseSockStat parses /proc/net/sockstat{,6}:
+//
+//	sockets: used 18
+//	TCP: inuse 4 orphan 0 tw 0 alloc 4 mem 0
+func parseSockStat(r io.Reader) (map[string]float64, error) {
+	sc := bufio.NewScanner(r)
+
+	stats := make(map[string]float64)
+
+	for sc.Scan() {
+		splitted := strings.Fields(sc.Text())
+
+		// proto followed by key/value pairs
+		if len(splitted) < 3 || len(splitted)%2 != 1 { //nolint
+			continue
+		}
+
+		proto := strings.TrimSuffix(splitted[0], ":")
+
+		for i := 1; i < len(splitted); i += 2 {
+			val, err := strconv.ParseFloat(splitted[i+1], 64)
+			if err != nil {
+				return nil, err
+			}
+
+			stats[fmt.Sprintf("%s_%s", proto, splitted[i])] = val
+		}
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
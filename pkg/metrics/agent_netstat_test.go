@@ -0,0 +1,89 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetNetStats(t *testing.T) {
+	stats, err := getNetStats()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := stats["Tcp_RetransSegs"]; !ok {
+		t.Error("expect Tcp_RetransSegs to match the default fields")
+	}
+}
+
+func TestGetSockStats(t *testing.T) {
+	stats, err := getSockStats()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := stats["TCP_inuse"]; !ok {
+		t.Error("expect TCP_inuse to match the default fields")
+	}
+
+	for k := range stats {
+		if !strings.HasPrefix(k, "sockets_") && !strings.Contains(k, "_inuse") && !strings.Contains(k, "_mem") && !strings.HasPrefix(k, "TCP") {
+			t.Errorf("expect %s to be filtered by the default fields", k)
+		}
+	}
+}
+
+func TestParseNetStat(t *testing.T) {
+	data := `Tcp: RtoAlgorithm RtoMin RtoMax MaxConn ActiveOpens RetransSegs
+Tcp: 1 200 120000 -1 42 7
+TcpExt: SyncookiesSent ListenOverflows
+TcpExt: 3 11
+`
+
+	stats, err := parseNetStat(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats["Tcp_MaxConn"] != -1 || stats["Tcp_RetransSegs"] != 7 || stats["TcpExt_ListenOverflows"] != 11 {
+		t.Errorf("unexpected netstat: %v", stats)
+	}
+
+	if _, err := parseNetStat(strings.NewReader("Tcp: RtoAlgorithm RtoMin\nTcp: 1\n")); err == nil {
+		t.Error("expect error on mismatched header and values")
+	}
+}
+
+func TestParseSNMP6(t *testing.T) {
+	data := `Ip6InReceives                   	3
+Icmp6InErrors                   	1
+Udp6RcvbufErrors                	5
+UdpLite6InErrors                	2
+`
+
+	stats, err := parseSNMP6(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats["Ip6_InReceives"] != 3 || stats["Icmp6_InErrors"] != 1 || stats["Udp6_RcvbufErrors"] != 5 || stats["UdpLite6_InErrors"] != 2 {
+		t.Errorf("unexpected snmp6: %v", stats)
+	}
+}
+
+func TestParseSockStat(t *testing.T) {
+	data := `sockets: used 18
+TCP: inuse 4 orphan 0 tw 9 alloc 4 mem 1
+FRAG: inuse 0 memory 0
+`
+
+	stats, err := parseSockStat(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats["sockets_used"] != 18 || stats["TCP_tw"] != 9 || stats["TCP_mem"] != 1 || len(stats) != 8 {
+		t.Errorf("unexpected sockstat: %v", stats)
+	}
+}
@@ -0,0 +1,366 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// the mount and network namespaces of pid 1 rather than v-agent's own, in k8s kubelet mounts nfs pvs on the host so
+// the daemonset runs with hostPID for /proc/1 to be the host's init
+const (
+	procNetRPCNFSPath   = "/proc/1/net/rpc/nfs"
+	procMountstatsPath  = "/proc/1/mountstats"
+	mountstatsMsPerSec  = 1000
+	mountstatsOpFields  = 8 // ops trans timeouts bytes_sent bytes_recv queue rtt execute, errors since statvers=1.1 on newer kernels
+	mountstatsBytesSize = 8 // normal read/write, direct read/write, server read/write, read/write pages
+)
+
+// nfsProcNames op names of the procN lines of /proc/net/rpc/nfs in kernel order
+//
+// proc4 follows the NFSPROC4_CLNT_* enum in include/linux/nfs4.h, ops a newer kernel appends are skipped
+var nfsProcNames = map[string][]string{
+	"2": {
+		"null", "getattr", "setattr", "root", "lookup", "readlink", "read", "writecache", "write", "create", "remove",
+		"rename", "link", "symlink", "mkdir", "rmdir", "readdir", "statfs",
+	},
+	"3": {
+		"null", "getattr", "setattr", "lookup", "access", "readlink", "read", "write", "create", "mkdir", "symlink",
+		"mknod", "remove", "rmdir", "rename", "link", "readdir", "readdirplus", "fsstat", "fsinfo", "pathconf", "commit",
+	},
+	"4": {
+		"null", "read", "write", "commit", "open", "open_confirm", "open_noattr", "open_downgrade", "close", "setattr",
+		"fsinfo", "renew", "setclientid", "setclientid_confirm", "lock", "lockt", "locku", "access", "getattr", "lookup",
+		"lookup_root", "remove", "rename", "link", "symlink", "create", "pathconf", "statfs", "readlink", "readdir",
+		"server_caps", "delegreturn", "getacl", "setacl", "fs_locations", "release_lockowner", "secinfo", "fsid_present",
+		"exchange_id", "create_session", "destroy_session", "sequence", "get_lease_time", "reclaim_complete", "layoutget",
+		"getdeviceinfo", "layoutcommit", "layoutreturn", "secinfo_no_name", "test_stateid", "free_stateid",
+		"getdevicelist", "bind_conn_to_session", "destroy_clientid", "seek", "allocate", "deallocate", "layoutstats",
+		"clone", "copy", "offload_cancel", "lookupp", "layouterror", "copy_notify", "getxattr", "setxattr", "listxattrs",
+		"removexattr", "read_plus",
+	},
+}
+
+// NFSClientStats /proc/net/rpc/nfs, client wide RPC counters since the nfs module was loaded
+type NFSClientStats struct {
+	Packets        float64
+	UDPPackets     float64
+	TCPPackets     float64
+	TCPConnections float64
+
+	RPCCalls        float64
+	RPCRetransmits  float64
+	RPCAuthRefreshs float64
+
+	Requests []*NFSRequests
+}
+
+// NFSRequests calls of an op of an NFS version
+type NFSRequests struct {
+	Version string
+	Op      string
+	Count   float64
+}
+
+// NFSMountStats a nfs/nfs4 entry of /proc/[pid]/mountstats
+//
+// https://utcc.utoronto.ca/~cks/space/blog/linux/NFSMountstatsIndex
+type NFSMountStats struct {
+	Export     string // server:/path
+	Mountpoint string
+	Type       string // nfs or nfs4
+	Age        float64
+
+	NormalReadBytes  float64 // read(2)/write(2) through the page cache
+	NormalWriteBytes float64
+	DirectReadBytes  float64 // O_DIRECT
+	DirectWriteBytes float64
+	ServerReadBytes  float64 // sent to/received from the server
+	ServerWriteBytes float64
+
+	Transport *NFSTransportStats
+	Ops       []*NFSOpStats
+}
+
+// NFSTransportStats the xprt: line of a mount, only the fields common to udp and tcp
+type NFSTransportStats struct {
+	Protocol string
+	Sends    float64
+	Receives float64
+	BadXIDs  float64 // replies that didn't match a request
+}
+
+// NFSOpStats a line of the per-op statistics of a mount
+type NFSOpStats struct {
+	Op              string
+	Operations      float64
+	Retransmissions float64 // transmissions - operations
+	MajorTimeouts   float64
+	BytesSent       float64
+	BytesReceived   float64
+	QueueSeconds    float64 // cumulative time waiting to be sent
+	RTTSeconds      float64 // cumulative time from send to reply
+	ExecuteSeconds  float64 // cumulative time from queue to completion
+	Errors          float64
+}
+
+// getNFSClientStats reads /proc/1/net/rpc/nfs, returns nil if the nfs client module isn't loaded
+func getNFSClientStats(path string) (*NFSClientStats, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer fd.Close() //nolint
+
+	return parseNFSClientStats(fd)
+}
+
+// parseNFSClientStats parses /proc/net/rpc/nfs:
+//
+//	net 18628 0 18628 6
+//	rpc 4329785 0 4338291
+//	proc3 22 1 4084749 29200 ...
+//	proc4 61 1 0 0 ...
+func parseNFSClientStats(r io.Reader) (*NFSClientStats, error) {
+	var stats NFSClientStats
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 { //nolint
+			continue
+		}
+
+		values, err := parseFloats(fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed %s line: %w", fields[0], err)
+		}
+
+		switch {
+		case fields[0] == "net":
+			if len(values) < 4 { //nolint
+				return nil, fmt.Errorf("malformed net line, expected 4 fields, got %d", len(values))
+			}
+
+			stats.Packets, stats.UDPPackets, stats.TCPPackets, stats.TCPConnections = values[0], values[1], values[2], values[3]
+		case fields[0] == "rpc":
+			if len(values) < 3 { //nolint
+				return nil, fmt.Errorf("malformed rpc line, expected 3 fields, got %d", len(values))
+			}
+
+			stats.RPCCalls, stats.RPCRetransmits, stats.RPCAuthRefreshs = values[0], values[1], values[2]
+		case strings.HasPrefix(fields[0], "proc"):
+			version := strings.TrimPrefix(fields[0], "proc")
+
+			// the first value is the number of ops that follow
+			if int(values[0]) != len(values)-1 {
+				return nil, fmt.Errorf("malformed %s line, expected %d ops, got %d", fields[0], int(values[0]), len(values)-1)
+			}
+
+			names := nfsProcNames[version]
+
+			for i, v := range values[1:] {
+				if i >= len(names) {
+					break
+				}
+
+				stats.Requests = append(stats.Requests, &NFSRequests{Version: version, Op: names[i], Count: v})
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// getNFSMountStats reads the nfs and nfs4 mounts of /proc/1/mountstats
+func getNFSMountStats(path string) ([]*NFSMountStats, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close() //nolint
+
+	return parseMountstats(fd)
+}
+
+// parseMountstats parses the nfs and nfs4 mounts of /proc/[pid]/mountstats, other filesystems only have the device line:
+//
+//	device 10.0.0.1:/export mounted on /mnt/vfs with fstype nfs4 statvers=1.1
+//		age:	13968
+//		bytes:	1207640230 0 0 0 1210214218 0 295483 0
+//		xprt:	tcp 832 0 1 0 11 6428 6428 0 12154 0 24 26 5726
+//		per-op statistics
+//		        NULL: 0 0 0 0 0 0 0 0
+//		        READ: 1298 1298 0 207680 1210292152 6 79386 79407 0
+func parseMountstats(r io.Reader) ([]*NFSMountStats, error) {
+	var stats []*NFSMountStats
+
+	var mount *NFSMountStats
+
+	inOps := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		if fields[0] == "device" {
+			mount, inOps = nil, false
+
+			// device X mounted on Y with fstype Z [statvers=1.1]
+			if len(fields) < 8 || fields[2] != "mounted" || (fields[7] != "nfs" && fields[7] != "nfs4") { //nolint
+				continue
+			}
+
+			mount = &NFSMountStats{Export: unescapeMountinfo(fields[1]), Mountpoint: unescapeMountinfo(fields[4]), Type: fields[7]}
+			stats = append(stats, mount)
+
+			continue
+		}
+
+		if mount == nil {
+			continue
+		}
+
+		switch {
+		case fields[0] == "age:" && len(fields) == 2: //nolint
+			age, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil {
+				return nil, err
+			}
+
+			mount.Age = age
+		case fields[0] == "bytes:":
+			values, err := parseFloats(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			if len(values) < mountstatsBytesSize {
+				return nil, fmt.Errorf("malformed mountstats bytes line, expected %d fields, got %d", mountstatsBytesSize, len(values))
+			}
+
+			mount.NormalReadBytes, mount.NormalWriteBytes = values[0], values[1]
+			mount.DirectReadBytes, mount.DirectWriteBytes = values[2], values[3]
+			mount.ServerReadBytes, mount.ServerWriteBytes = values[4], values[5]
+		case fields[0] == "xprt:":
+			xprt, err := parseMountstatsTransport(fields[1:])
+			if err != nil {
+				return nil, err
+			}
+
+			mount.Transport = xprt
+		case fields[0] == "per-op":
+			inOps = true
+		case inOps && strings.HasSuffix(fields[0], ":"):
+			op, err := parseMountstatsOp(fields)
+			if err != nil {
+				return nil, err
+			}
+
+			mount.Ops = append(mount.Ops, op)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// parseMountstatsTransport parses the fields after xprt:, the position of sends, recvs and bad_xids depends on the protocol:
+//
+//	tcp: port bind_count connect_count connect_time idle_time sends recvs bad_xids ...
+//	udp: port bind_count sends recvs bad_xids ...
+func parseMountstatsTransport(fields []string) (*NFSTransportStats, error) {
+	if len(fields) == 0 {
+		return nil, errors.New("malformed mountstats xprt line, missing protocol")
+	}
+
+	var sends int
+
+	switch fields[0] {
+	case "tcp", "rdma":
+		sends = 6
+	case "udp":
+		sends = 3
+	default:
+		return &NFSTransportStats{Protocol: fields[0]}, nil
+	}
+
+	if len(fields) < sends+3 { //nolint
+		return nil, fmt.Errorf("malformed mountstats xprt %s line, got %d fields", fields[0], len(fields))
+	}
+
+	values, err := parseFloats(fields[sends : sends+3])
+	if err != nil {
+		return nil, err
+	}
+
+	return &NFSTransportStats{Protocol: fields[0], Sends: values[0], Receives: values[1], BadXIDs: values[2]}, nil
+}
+
+// parseMountstatsOp parses a per-op line, OP: ops trans timeouts bytes_sent bytes_recv queue rtt execute [errors]
+func parseMountstatsOp(fields []string) (*NFSOpStats, error) {
+	if len(fields) < mountstatsOpFields+1 {
+		return nil, fmt.Errorf("malformed mountstats op line, expected %d fields, got %d", mountstatsOpFields, len(fields)-1)
+	}
+
+	values, err := parseFloats(fields[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	op := NFSOpStats{
+		Op:             strings.ToLower(strings.TrimSuffix(fields[0], ":")),
+		Operations:     values[0],
+		MajorTimeouts:  values[2],
+		BytesSent:      values[3],
+		BytesReceived:  values[4],
+		QueueSeconds:   values[5] / mountstatsMsPerSec,
+		RTTSeconds:     values[6] / mountstatsMsPerSec,
+		ExecuteSeconds: values[7] / mountstatsMsPerSec,
+	}
+
+	if values[1] > values[0] {
+		op.Retransmissions = values[1] - values[0]
+	}
+
+	if len(values) > mountstatsOpFields {
+		op.Errors = values[mountstatsOpFields]
+	}
+
+	return &op, nil
+}
+
+// parseFloats parses every field as a float64
+func parseFloats(fields []string) ([]float64, error) {
+	values := make([]float64, 0, len(fields))
+
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(f, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
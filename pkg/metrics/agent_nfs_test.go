@@ -0,0 +1,136 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseNFSClientStats(t *testing.T) {
+	stats, err := parseNFSClientStats(strings.NewReader(`net 18628 0 18628 6
+rpc 4329785 12 4338291
+proc3 22 1 4084749 29200 94754 32580 186 47747 7981 8639 0 6356 0 6962 0 7958 0 0 241 4 4 2 39
+proc4 3 1 95 7
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Packets != 18628 || stats.TCPPackets != 18628 || stats.TCPConnections != 6 {
+		t.Errorf("unexpected net %+v", stats)
+	}
+
+	if stats.RPCCalls != 4329785 || stats.RPCRetransmits != 12 || stats.RPCAuthRefreshs != 4338291 {
+		t.Errorf("unexpected rpc %+v", stats)
+	}
+
+	if len(stats.Requests) != 25 {
+		t.Fatalf("expected 25 requests, got %d", len(stats.Requests))
+	}
+
+	if r := stats.Requests[1]; r.Version != "3" || r.Op != "getattr" || r.Count != 4084749 {
+		t.Errorf("unexpected proc3 getattr %+v", r)
+	}
+
+	if r := stats.Requests[21]; r.Op != "commit" || r.Count != 39 {
+		t.Errorf("unexpected proc3 commit %+v", r)
+	}
+
+	if r := stats.Requests[24]; r.Version != "4" || r.Op != "write" || r.Count != 7 {
+		t.Errorf("unexpected proc4 write %+v", r)
+	}
+
+	for _, b := range []string{"net 1 2\n", "rpc 1 x 3\n", "proc3 22 1 2\n"} {
+		if _, err := parseNFSClientStats(strings.NewReader(b)); err == nil {
+			t.Errorf("%q: expected error", b)
+		}
+	}
+
+	if stats, err := getNFSClientStats(filepath.Join(t.TempDir(), "nfs")); err != nil || stats != nil {
+		t.Errorf("expected nil without the nfs module, got %+v %v", stats, err)
+	}
+}
+
+func TestParseMountstats(t *testing.T) {
+	stats, err := parseMountstats(strings.NewReader(`device rootfs mounted on / with fstype rootfs
+device proc mounted on /proc with fstype proc
+device 10.0.0.1:/export mounted on /mnt/vfs with fstype nfs4 statvers=1.1
+	opts:	rw,vers=4.1,rsize=1048576,wsize=1048576,namlen=255,acregmin=3,acregmax=60,acdirmin=30,acdirmax=60,hard,proto=tcp,timeo=600,retrans=2,sec=sys
+	age:	13968
+	caps:	caps=0xfff7,wtmult=512,dtsize=32768,bsize=0,namlen=255
+	sec:	flavor=1,pseudoflavor=1
+	events:	52 226 0 0 1 13 398 0 0 331 0 47 0 0 77 0 0 77 0 0 0 0 0 0 0 0 0
+	bytes:	1207640230 4096 0 8192 1210214218 12288 295483 3
+	RPC iostats version: 1.1  p/v: 100003/4 (nfs)
+	xprt:	tcp 832 0 1 0 11 6428 6426 2 12154 0 24 26 5726
+	per-op statistics
+	        NULL: 0 0 0 0 0 0 0 0 0
+	        READ: 1298 1300 1 207680 1210292152 6 79386 79407 3
+	       WRITE: 0 0 0 0 0 0 0 0 0
+
+device 10.0.0.2:/legacy mounted on /mnt/legacy\040share with fstype nfs statvers=1.0
+	age:	60
+	bytes:	0 0 0 0 0 0 0 0
+	xprt:	udp 0 0 10 10 0 10 0
+	per-op statistics
+	     GETATTR: 10 10 0 1280 1120 0 5 6
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 nfs mounts, got %d", len(stats))
+	}
+
+	vfs := stats[0]
+	if vfs.Export != "10.0.0.1:/export" || vfs.Mountpoint != "/mnt/vfs" || vfs.Type != "nfs4" || vfs.Age != 13968 {
+		t.Errorf("unexpected mount %+v", vfs)
+	}
+
+	if vfs.NormalReadBytes != 1207640230 || vfs.NormalWriteBytes != 4096 || vfs.DirectWriteBytes != 8192 || vfs.ServerReadBytes != 1210214218 || vfs.ServerWriteBytes != 12288 {
+		t.Errorf("unexpected bytes %+v", vfs)
+	}
+
+	if x := vfs.Transport; x == nil || x.Protocol != "tcp" || x.Sends != 6428 || x.Receives != 6426 || x.BadXIDs != 2 {
+		t.Errorf("unexpected tcp transport %+v", x)
+	}
+
+	if len(vfs.Ops) != 3 {
+		t.Fatalf("expected 3 ops, got %d", len(vfs.Ops))
+	}
+
+	read := vfs.Ops[1]
+	if read.Op != "read" || read.Operations != 1298 || read.Retransmissions != 2 || read.MajorTimeouts != 1 || read.BytesReceived != 1210292152 {
+		t.Errorf("unexpected read op %+v", read)
+	}
+
+	if read.QueueSeconds != 0.006 || read.RTTSeconds != 79.386 || read.ExecuteSeconds != 79.407 || read.Errors != 3 {
+		t.Errorf("unexpected read op times %+v", read)
+	}
+
+	legacy := stats[1]
+	if legacy.Mountpoint != "/mnt/legacy share" {
+		t.Errorf("expected unescaped mountpoint, got %q", legacy.Mountpoint)
+	}
+
+	if x := legacy.Transport; x == nil || x.Protocol != "udp" || x.Sends != 10 || x.Receives != 10 || x.BadXIDs != 0 {
+		t.Errorf("unexpected udp transport %+v", x)
+	}
+
+	// statvers=1.0 has no errors field
+	if len(legacy.Ops) != 1 || legacy.Ops[0].Op != "getattr" || legacy.Ops[0].Operations != 10 || legacy.Ops[0].Errors != 0 {
+		t.Errorf("unexpected legacy ops %+v", legacy.Ops)
+	}
+
+	for _, b := range []string{
+		"device a:/b mounted on /c with fstype nfs\n\tbytes:\t1 2 3\n",
+		"device a:/b mounted on /c with fstype nfs\n\txprt:\ttcp 1 2\n",
+		"device a:/b mounted on /c with fstype nfs\n\tper-op statistics\n\tREAD: 1 2 3\n",
+	} {
+		if _, err := parseMountstats(strings.NewReader(b)); err == nil {
+			t.Errorf("%q: expected error", b)
+		}
+	}
+}
@@ -5,8 +5,11 @@ import (
 	"bufio"
 	"io"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
 )
 
 // NICMetrics metrics for the underlying NIC for the guest
@@ -36,6 +39,170 @@ type NICMetrics struct {
 	MulticastRX  uint
 }
 
+// nicHasDevice returns true if the interface is backed by a device (physical nic, virtio, sr-iov vf)
+//
+// veth, bridges, tunnels, cni interfaces etc have no /sys/class/net/<iface>/device
+var nicHasDevice = func(iface string) bool {
+	_, err := os.Stat(filepath.Join(sysClassNetPath, iface, "device"))
+
+	return err == nil
+}
+
+// nicRollup the series of interfaces matching nic.aggregate, carried across gathers
+//
+// summing the cumulative counters of the current members would make the series go down whenever an interface
+// is removed (pod churn) and rate() would see a counter reset, instead every gather adds how much each member
+// increased since the previous gather so the series only goes up
+type nicRollup struct {
+	total NICMetrics
+	last  map[string]NICMetrics
+	seen  bool // an interface matched at least once
+}
+
+var nicAggregateRollup = &nicRollup{}
+
+// update adds the increase of every member since the last update, members seen for the first time add their
+// counters as is
+func (r *nicRollup) update(members []NICMetrics) {
+	last := make(map[string]NICMetrics, len(members))
+
+	for i := range members {
+		prev := r.last[members[i].Interface]
+
+		delta := members[i].delta(&prev)
+		r.total.add(&delta)
+
+		last[members[i].Interface] = members[i]
+		r.seen = true
+	}
+
+	r.last = last
+}
+
+// getFilteredNICStats returns nic stats after applying nic.include, nic.exclude, nic.physical_only and nic.aggregate
+func getFilteredNICStats() ([]NICMetrics, error) {
+	stats, err := getNICStats()
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := newIncludeExcludeFilter(config.GetNICInclude(), config.GetNICExclude())
+	if err != nil {
+		return nil, err
+	}
+
+	var aggregate *includeExcludeFilter
+	if config.GetNICAggregate() != "" {
+		if aggregate, err = newIncludeExcludeFilter(config.GetNICAggregate(), ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return filterNICStats(stats, filter, aggregate, nicAggregateRollup, config.GetNICAggregateName(), config.NICPhysicalOnly()), nil
+}
+
+// filterNICStats drops interfaces not matching filter, rolls interfaces matching aggregate up into rollup reported as
+// a single interface named aggregateName and, if physicalOnly, drops remaining interfaces without a backing device
+func filterNICStats(stats []NICMetrics, filter, aggregate *includeExcludeFilter, rollup *nicRollup, aggregateName string, physicalOnly bool) []NICMetrics {
+	var filtered, members []NICMetrics
+
+	for i := range stats {
+		if !filter.match(stats[i].Interface) {
+			continue
+		}
+
+		if aggregate != nil && aggregate.match(stats[i].Interface) {
+			members = append(members, stats[i])
+
+			continue
+		}
+
+		if physicalOnly && !nicHasDevice(stats[i].Interface) {
+			continue
+		}
+
+		filtered = append(filtered, stats[i])
+	}
+
+	if aggregate != nil {
+		rollup.update(members)
+
+		if rollup.seen {
+			rolledUp := rollup.total
+			rolledUp.Interface = aggregateName
+
+			filtered = append(filtered, rolledUp)
+		}
+	}
+
+	return filtered
+}
+
+// add sums the counters of o into n
+func (n *NICMetrics) add(o *NICMetrics) {
+	n.Bytes += o.Bytes
+	n.BytesTX += o.BytesTX
+	n.BytesRX += o.BytesRX
+	n.Packets += o.Packets
+	n.PacketsTX += o.PacketsTX
+	n.PacketsRX += o.PacketsRX
+	n.Errors += o.Errors
+	n.ErrorsTX += o.ErrorsTX
+	n.ErrorsRX += o.ErrorsRX
+	n.Drop += o.Drop
+	n.DropTX += o.DropTX
+	n.DropRX += o.DropRX
+	n.FIFO += o.FIFO
+	n.FIFOTX += o.FIFOTX
+	n.FIFORX += o.FIFORX
+	n.FrameRX += o.FrameRX
+	n.CollsTX += o.CollsTX
+	n.Compressed += o.Compressed
+	n.CompressedTX += o.CompressedTX
+	n.CompressedRX += o.CompressedRX
+	n.CarrierTX += o.CarrierTX
+	n.MulticastRX += o.MulticastRX
+}
+
+// delta returns the increase of every counter of n since prev
+func (n *NICMetrics) delta(prev *NICMetrics) NICMetrics {
+	return NICMetrics{
+		Interface:    n.Interface,
+		Bytes:        counterDelta(n.Bytes, prev.Bytes),
+		BytesTX:      counterDelta(n.BytesTX, prev.BytesTX),
+		BytesRX:      counterDelta(n.BytesRX, prev.BytesRX),
+		Packets:      counterDelta(n.Packets, prev.Packets),
+		PacketsTX:    counterDelta(n.PacketsTX, prev.PacketsTX),
+		PacketsRX:    counterDelta(n.PacketsRX, prev.PacketsRX),
+		Errors:       counterDelta(n.Errors, prev.Errors),
+		ErrorsTX:     counterDelta(n.ErrorsTX, prev.ErrorsTX),
+		ErrorsRX:     counterDelta(n.ErrorsRX, prev.ErrorsRX),
+		Drop:         counterDelta(n.Drop, prev.Drop),
+		DropTX:       counterDelta(n.DropTX, prev.DropTX),
+		DropRX:       counterDelta(n.DropRX, prev.DropRX),
+		FIFO:         counterDelta(n.FIFO, prev.FIFO),
+		FIFOTX:       counterDelta(n.FIFOTX, prev.FIFOTX),
+		FIFORX:       counterDelta(n.FIFORX, prev.FIFORX),
+		FrameRX:      counterDelta(n.FrameRX, prev.FrameRX),
+		CollsTX:      counterDelta(n.CollsTX, prev.CollsTX),
+		Compressed:   counterDelta(n.Compressed, prev.Compressed),
+		CompressedTX: counterDelta(n.CompressedTX, prev.CompressedTX),
+		CompressedRX: counterDelta(n.CompressedRX, prev.CompressedRX),
+		CarrierTX:    counterDelta(n.CarrierTX, prev.CarrierTX),
+		MulticastRX:  counterDelta(n.MulticastRX, prev.MulticastRX),
+	}
+}
+
+// counterDelta returns the increase of a counter, a counter lower than prev was reset (interface recreated with the
+// same name) and counts from zero
+func counterDelta(cur, prev uint) uint {
+	if cur < prev {
+		return cur
+	}
+
+	return cur - prev
+}
+
 func getNICStats() ([]NICMetrics, error) {
 	procNetDev, err := os.Open("/proc/net/dev")
 	if err != nil {
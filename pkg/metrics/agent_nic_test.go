@@ -11,3 +11,81 @@ func TestGetNICStats(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestFilterNICStats(t *testing.T) {
+	orig := nicHasDevice
+	defer func() { nicHasDevice = orig }()
+
+	nicHasDevice = func(iface string) bool {
+		return iface == "eth0"
+	}
+
+	stats := []NICMetrics{
+		{Interface: "lo", Bytes: 1},
+		{Interface: "eth0", Bytes: 10},
+		{Interface: "br0", Bytes: 100},
+		{Interface: "veth1234", Bytes: 1000, BytesRX: 600, BytesTX: 400},
+		{Interface: "cali5678", Bytes: 2000, BytesRX: 1000, BytesTX: 1000},
+	}
+
+	filter, err := newIncludeExcludeFilter("", "^lo$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aggregate, err := newIncludeExcludeFilter("^(veth|cali)", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := make(map[string]NICMetrics)
+	for _, n := range filterNICStats(stats, filter, aggregate, &nicRollup{}, "virtual", true) {
+		got[n.Interface] = n
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expect eth0 and virtual, got %v", got)
+	}
+
+	if got["eth0"].Bytes != 10 {
+		t.Errorf("unexpected eth0: %+v", got["eth0"])
+	}
+
+	if v := got["virtual"]; v.Bytes != 3000 || v.BytesRX != 1600 || v.BytesTX != 1400 {
+		t.Errorf("unexpected virtual: %+v", v)
+	}
+
+	// without physical_only or aggregation everything but lo is kept
+	if n := len(filterNICStats(stats, filter, nil, &nicRollup{}, "virtual", false)); n != 4 {
+		t.Errorf("expect 4 interfaces, got %d", n)
+	}
+}
+
+func TestNICRollup(t *testing.T) {
+	var r nicRollup
+
+	r.update([]NICMetrics{{Interface: "veth1", Bytes: 100}, {Interface: "veth2", Bytes: 200}})
+
+	if r.total.Bytes != 300 {
+		t.Fatalf("expected 300, got %d", r.total.Bytes)
+	}
+
+	// veth1 deleted, veth3 created, veth2 recreated with reset counters, the total must not go down
+	r.update([]NICMetrics{{Interface: "veth2", Bytes: 50}, {Interface: "veth3", Bytes: 10}})
+
+	if r.total.Bytes != 360 {
+		t.Fatalf("expected 360, got %d", r.total.Bytes)
+	}
+
+	r.update([]NICMetrics{{Interface: "veth2", Bytes: 80}})
+
+	if r.total.Bytes != 390 {
+		t.Fatalf("expected 390, got %d", r.total.Bytes)
+	}
+
+	r.update(nil)
+
+	if r.total.Bytes != 390 || !r.seen {
+		t.Errorf("expected the total to be kept without members, got %d", r.total.Bytes)
+	}
+}
@@ -0,0 +1,232 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/vultr/v-agent/pkg/util"
+)
+
+const (
+	procUptimePath = "/proc/uptime"
+	sysDMIPath     = "/sys/devices/virtual/dmi/id"
+)
+
+// osReleasePaths /etc/os-release takes precedence, /usr/lib/os-release is the fallback
+//
+// https://www.freedesktop.org/software/systemd/man/latest/os-release.html
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// osReleaseFields fields of os-release exposed as labels of v_os_release_info
+var osReleaseFields = []string{"ID", "ID_LIKE", "NAME", "PRETTY_NAME", "VERSION", "VERSION_ID", "VERSION_CODENAME"}
+
+// dmiFields files of /sys/devices/virtual/dmi/id exposed as labels of v_dmi_info, serials and uuids are root only and left out
+var dmiFields = []string{"bios_version", "bios_date", "sys_vendor", "product_name", "product_version", "board_vendor", "board_name", "chassis_vendor", "chassis_type"}
+
+// NodeInfo identity of the host
+type NodeInfo struct {
+	Uname     map[string]string // sysname, release, version, machine, nodename, domainname
+	OSRelease map[string]string // osReleaseFields, empty values if unset
+	DMI       map[string]string // bios_vendor (util.GetBIOSVendor) and dmiFields, nil if DMI isn't available (some arm hosts)
+	BootTime  float64           // unix seconds
+	Uptime    float64           // seconds
+}
+
+// getNodeInfo returns uname, os-release, DMI, boot time and uptime
+func getNodeInfo() (*NodeInfo, error) {
+	var err error
+
+	var ni NodeInfo
+
+	if ni.Uname, err = getUname(); err != nil {
+		return nil, err
+	}
+
+	if ni.OSRelease, err = getOSRelease(osReleasePaths); err != nil {
+		return nil, err
+	}
+
+	if ni.DMI, err = getDMIInfo(sysDMIPath); err != nil {
+		return nil, err
+	}
+
+	// the same value check_vendor uses
+	if ni.DMI != nil {
+		if vendor, err := util.GetBIOSVendor(); err == nil {
+			ni.DMI["bios_vendor"] = *vendor
+		}
+	}
+
+	procStat, err := getProcStat()
+	if err != nil {
+		return nil, err
+	}
+
+	ni.BootTime = float64(procStat.BootTime)
+
+	fd, err := os.Open(procUptimePath)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close() //nolint
+
+	if ni.Uptime, err = parseUptime(fd); err != nil {
+		return nil, err
+	}
+
+	return &ni, nil
+}
+
+func getUname() (map[string]string, error) {
+	var uts syscall.Utsname
+
+	if err := syscall.Uname(&uts); err != nil {
+		return nil, err
+	}
+
+	return map[string]string{
+		"sysname":    utsnameToString(uts.Sysname),
+		"release":    utsnameToString(uts.Release),
+		"version":    utsnameToString(uts.Version),
+		"machine":    utsnameToString(uts.Machine),
+		"nodename":   utsnameToString(uts.Nodename),
+		"domainname": utsnameToString(uts.Domainname),
+	}, nil
+}
+
+// utsnameToString converts a NUL terminated utsname field, int8 on most architectures and uint8 on arm
+func utsnameToString[T int8 | uint8](field [65]T) string {
+	var sb strings.Builder
+
+	for _, c := range field {
+		if c == 0 {
+			break
+		}
+
+		sb.WriteByte(byte(c))
+	}
+
+	return sb.String()
+}
+
+// getOSRelease reads the first os-release of paths that exists
+func getOSRelease(paths []string) (map[string]string, error) {
+	release := make(map[string]string)
+
+	for _, p := range paths {
+		fd, err := os.Open(p) //nolint
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		release, err = parseOSRelease(fd)
+		fd.Close() //nolint
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+
+		break
+	}
+
+	for _, f := range osReleaseFields {
+		if _, ok := release[f]; !ok {
+			release[f] = ""
+		}
+	}
+
+	return release, nil
+}
+
+// parseOSRelease parses os-release, a newline separated list of shell compatible variable assignments:
+//
+//	NAME="Ubuntu"
+//	VERSION_ID="22.04"
+//	ID=ubuntu
+func parseOSRelease(r io.Reader) (map[string]string, error) {
+	sc := bufio.NewScanner(r)
+
+	release := make(map[string]string)
+
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		k, v, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+
+		switch {
+		case len(v) >= 2 && v[0] == '"' && v[len(v)-1] == '"':
+			unquoted, err := strconv.Unquote(v)
+			if err != nil {
+				// shell escapes that aren't valid go escapes, keep the raw value
+				unquoted = v[1 : len(v)-1]
+			}
+
+			v = unquoted
+		case len(v) >= 2 && v[0] == '\'' && v[len(v)-1] == '\'':
+			v = v[1 : len(v)-1]
+		}
+
+		release[k] = v
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// getDMIInfo reads DMI identification from root (/sys/devices/virtual/dmi/id), returns nil if it doesn't exist
+func getDMIInfo(root string) (map[string]string, error) {
+	if _, err := os.Stat(root); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	dmi := map[string]string{"bios_vendor": ""}
+
+	for _, f := range dmiFields {
+		// unreadable files (permissions) and missing files are left empty
+		v, _ := readSysString(filepath.Join(root, f))
+		dmi[f] = v
+	}
+
+	return dmi, nil
+}
+
+// parseUptime parses /proc/uptime, uptime and idle time in seconds:
+//
+//	350735.47 234388.90
+func parseUptime(r io.Reader) (float64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return 0, errors.New("malformed uptime")
+	}
+
+	return strconv.ParseFloat(fields[0], 64)
+}
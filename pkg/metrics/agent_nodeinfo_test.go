@@ -0,0 +1,125 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseOSRelease(t *testing.T) {
+	release, err := parseOSRelease(strings.NewReader(`# comment
+PRETTY_NAME="Ubuntu 22.04.4 LTS"
+NAME="Ubuntu"
+VERSION_ID="22.04"
+VERSION="22.04.4 LTS (Jammy Jellyfish)"
+VERSION_CODENAME=jammy
+ID=ubuntu
+ID_LIKE='debian'
+HOME_URL="https://www.ubuntu.com/"
+
+INVALID
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k, expected := range map[string]string{
+		"PRETTY_NAME":      "Ubuntu 22.04.4 LTS",
+		"NAME":             "Ubuntu",
+		"VERSION_ID":       "22.04",
+		"VERSION":          "22.04.4 LTS (Jammy Jellyfish)",
+		"VERSION_CODENAME": "jammy",
+		"ID":               "ubuntu",
+		"ID_LIKE":          "debian",
+		"HOME_URL":         "https://www.ubuntu.com/",
+	} {
+		if release[k] != expected {
+			t.Errorf("%s: expected %q, got %q", k, expected, release[k])
+		}
+	}
+
+	if _, ok := release["INVALID"]; ok {
+		t.Error("expected lines without = to be skipped")
+	}
+}
+
+func TestGetOSRelease(t *testing.T) {
+	dir := t.TempDir()
+
+	usrLib := filepath.Join(dir, "os-release")
+	if err := os.WriteFile(usrLib, []byte("ID=debian\nVERSION_ID=\"12\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	release, err := getOSRelease([]string{filepath.Join(dir, "missing"), usrLib})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if release["ID"] != "debian" || release["VERSION_ID"] != "12" {
+		t.Errorf("unexpected os-release %+v", release)
+	}
+
+	// every label is always set
+	for _, f := range osReleaseFields {
+		if _, ok := release[f]; !ok {
+			t.Errorf("%s not set", f)
+		}
+	}
+}
+
+func TestGetDMIInfo(t *testing.T) {
+	dir := t.TempDir()
+
+	writeSysFiles(t, dir, map[string]string{
+		"sys_vendor":   "Vultr\n",
+		"product_name": "VC2\n",
+		"bios_version": "0.0.0\n",
+		"chassis_type": "1\n",
+	})
+
+	dmi, err := getDMIInfo(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dmi["sys_vendor"] != "Vultr" || dmi["product_name"] != "VC2" || dmi["chassis_type"] != "1" || dmi["board_name"] != "" {
+		t.Errorf("unexpected dmi %+v", dmi)
+	}
+
+	if len(dmi) != len(dmiFields)+1 {
+		t.Errorf("expected %d labels, got %d", len(dmiFields)+1, len(dmi))
+	}
+
+	if dmi, err := getDMIInfo(filepath.Join(dir, "missing")); err != nil || dmi != nil {
+		t.Errorf("expected nil without DMI, got %+v %v", dmi, err)
+	}
+}
+
+func TestParseUptime(t *testing.T) {
+	uptime, err := parseUptime(strings.NewReader("350735.47 234388.90\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uptime != 350735.47 {
+		t.Errorf("expected 350735.47, got %f", uptime)
+	}
+
+	if _, err := parseUptime(strings.NewReader("")); err == nil {
+		t.Error("expected error for empty uptime")
+	}
+}
+
+func TestUtsnameToString(t *testing.T) {
+	var field [65]int8
+	for i, c := range "x86_64" {
+		field[i] = int8(c)
+	}
+
+	if s := utsnameToString(field); s != "x86_64" {
+		t.Errorf("expected x86_64, got %q", s)
+	}
+}
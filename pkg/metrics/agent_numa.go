@@ -0,0 +1,180 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const sysDevicesNodePath = "/sys/devices/system/node"
+
+var (
+	numaNodeDirRe  = regexp.MustCompile(`^node([0-9]+)$`)
+	hugePagesDirRe = regexp.MustCompile(`^hugepages-([0-9]+)kB$`)
+)
+
+// NUMANodeStats memory, allocation stats and hugepage pools of a NUMA node
+//
+// https://docs.kernel.org/admin-guide/numastat.html
+type NUMANodeStats struct {
+	Node      string
+	Meminfo   map[string]MemInfoField // node*/meminfo, keyed by the field name as the kernel reports it
+	NUMAStat  map[string]uint64       // node*/numastat, numa_hit, numa_miss, numa_foreign, etc
+	HugePages []*HugePagePool
+}
+
+// HugePagePool a node*/hugepages/hugepages-<size>kB pool
+type HugePagePool struct {
+	PageSize uint64 // bytes
+	Total    uint64
+	Free     uint64
+	Surplus  uint64
+}
+
+// getNUMAStats reads every node under root (/sys/devices/system/node)
+//
+// returns an empty list on kernels built without NUMA support
+func getNUMAStats(root string) ([]*NUMANodeStats, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var stats []*NUMANodeStats
+
+	for i := range entries {
+		m := numaNodeDirRe.FindStringSubmatch(entries[i].Name())
+		if m == nil {
+			continue
+		}
+
+		dir := filepath.Join(root, entries[i].Name())
+
+		ns := NUMANodeStats{Node: m[1]}
+
+		if ns.Meminfo, err = readNUMAMeminfo(filepath.Join(dir, "meminfo")); err != nil {
+			return nil, err
+		}
+
+		// numastat has the same format as flat keyed cgroup files, numa_hit 34990980
+		if ns.NUMAStat, err = readFlatKeyed(filepath.Join(dir, "numastat")); err != nil {
+			return nil, err
+		}
+
+		if ns.HugePages, err = getHugePagePools(filepath.Join(dir, "hugepages")); err != nil {
+			return nil, err
+		}
+
+		stats = append(stats, &ns)
+	}
+
+	return stats, nil
+}
+
+func readNUMAMeminfo(path string) (map[string]MemInfoField, error) {
+	fd, err := os.Open(path) //nolint
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close() //nolint
+
+	return parseNUMAMeminfo(fd)
+}
+
+// parseNUMAMeminfo parses node*/meminfo, /proc/meminfo prefixed with the node:
+//
+//	Node 0 MemTotal:        6158152 kB
+//	Node 0 HugePages_Total:     0
+func parseNUMAMeminfo(r io.Reader) (map[string]MemInfoField, error) {
+	sc := bufio.NewScanner(r)
+
+	fields := make(map[string]MemInfoField)
+
+	for sc.Scan() {
+		splitted := strings.Fields(sc.Text())
+		if len(splitted) < 4 || splitted[0] != "Node" { //nolint
+			continue
+		}
+
+		val, err := strconv.ParseUint(splitted[3], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		field := MemInfoField{
+			Value: val,
+		}
+
+		if len(splitted) == 5 && splitted[4] == "kB" { //nolint
+			field.Value = val * 1024
+			field.Bytes = true
+		}
+
+		fields[strings.TrimSuffix(splitted[2], ":")] = field
+	}
+
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+// getHugePagePools reads every hugepages-<size>kB pool under dir, returns nil if hugetlbfs isn't supported
+func getHugePagePools(dir string) ([]*HugePagePool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var pools []*HugePagePool
+
+	for i := range entries {
+		m := hugePagesDirRe.FindStringSubmatch(entries[i].Name())
+		if m == nil {
+			continue
+		}
+
+		size, err := strconv.ParseUint(m[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		pool := HugePagePool{PageSize: size * 1024} //nolint
+
+		for _, f := range []struct {
+			name  string
+			value *uint64
+		}{
+			{"nr_hugepages", &pool.Total},
+			{"free_hugepages", &pool.Free},
+			{"surplus_hugepages", &pool.Surplus},
+		} {
+			v, err := readSysFloat(filepath.Join(dir, entries[i].Name(), f.name))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", entries[i].Name(), err)
+			}
+
+			*f.value = uint64(v)
+		}
+
+		pools = append(pools, &pool)
+	}
+
+	return pools, nil
+}
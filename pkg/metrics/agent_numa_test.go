@@ -0,0 +1,85 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseNUMAMeminfo(t *testing.T) {
+	fields, err := parseNUMAMeminfo(strings.NewReader(`Node 0 MemTotal:        6158152 kB
+Node 0 MemFree:         2920788 kB
+Node 0 Active(anon):         20 kB
+Node 0 HugePages_Total:     4
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := fields["MemTotal"]; v.Value != 6158152*1024 || !v.Bytes {
+		t.Errorf("unexpected MemTotal %+v", v)
+	}
+
+	if v := fields["Active(anon)"]; v.Value != 20*1024 {
+		t.Errorf("unexpected Active(anon) %+v", v)
+	}
+
+	if v := fields["HugePages_Total"]; v.Value != 4 || v.Bytes {
+		t.Errorf("unexpected HugePages_Total %+v", v)
+	}
+
+	if _, err := parseNUMAMeminfo(strings.NewReader("Node 0 MemTotal: x kB\n")); err == nil {
+		t.Error("expected error for malformed meminfo")
+	}
+}
+
+func TestGetNUMAStats(t *testing.T) {
+	dir := t.TempDir()
+
+	writeSysFiles(t, dir, map[string]string{
+		"online":         "0-1\n",
+		"node0/meminfo":  "Node 0 MemTotal:        6158152 kB\nNode 0 MemFree:         2920788 kB\n",
+		"node0/numastat": "numa_hit 34990980\nnuma_miss 0\nnuma_foreign 0\ninterleave_hit 1024\nlocal_node 34990980\nother_node 0\n",
+		"node0/hugepages/hugepages-2048kB/nr_hugepages":         "512\n",
+		"node0/hugepages/hugepages-2048kB/free_hugepages":       "500\n",
+		"node0/hugepages/hugepages-2048kB/surplus_hugepages":    "0\n",
+		"node0/hugepages/hugepages-1048576kB/nr_hugepages":      "2\n",
+		"node0/hugepages/hugepages-1048576kB/free_hugepages":    "1\n",
+		"node0/hugepages/hugepages-1048576kB/surplus_hugepages": "1\n",
+		"node1/meminfo":  "Node 1 MemTotal:        8388608 kB\n",
+		"node1/numastat": "numa_hit 10\nnuma_miss 5\n",
+	})
+
+	stats, err := getNUMAStats(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 nodes, got %d", len(stats))
+	}
+
+	n0 := stats[0]
+	if n0.Node != "0" || n0.Meminfo["MemFree"].Value != 2920788*1024 || n0.NUMAStat["interleave_hit"] != 1024 || len(n0.HugePages) != 2 {
+		t.Fatalf("unexpected node0 %+v", n0)
+	}
+
+	// ReadDir sorts by name, hugepages-1048576kB first
+	if p := n0.HugePages[0]; p.PageSize != 1024*1024*1024 || p.Total != 2 || p.Free != 1 || p.Surplus != 1 {
+		t.Errorf("unexpected 1G pool %+v", p)
+	}
+
+	if p := n0.HugePages[1]; p.PageSize != 2*1024*1024 || p.Total != 512 || p.Free != 500 {
+		t.Errorf("unexpected 2M pool %+v", p)
+	}
+
+	// no hugepages directory
+	if n1 := stats[1]; n1.Node != "1" || n1.NUMAStat["numa_miss"] != 5 || n1.HugePages != nil {
+		t.Errorf("unexpected node1 %+v", n1)
+	}
+
+	if stats, err := getNUMAStats(filepath.Join(dir, "missing")); err != nil || stats != nil {
+		t.Errorf("expected nil without numa, got %+v %v", stats, err)
+	}
+}
@@ -0,0 +1,300 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+)
+
+const (
+	procPath = "/proc"
+	// USER_HZ, /proc/[pid]/stat times are in clock ticks. The kernel fixes it at 100 for userspace on every
+	// architecture Go supports, sysconf(_SC_CLK_TCK) would need cgo
+	userHZ = 100
+)
+
+// ProcessGroupStats aggregates of every process matching a processes.groups entry
+type ProcessGroupStats struct {
+	Group            string
+	Count            uint64
+	Threads          uint64
+	RSSBytes         uint64
+	OpenFDs          uint64
+	CPUUserSeconds   float64 // includes processes that exited since v-agent started, see accumulateProcessGroupCPU
+	CPUSystemSeconds float64
+	StartTime        float64 // unix seconds of the oldest process, 0 if none are running
+	Restarts         uint64  // times StartTime changed since v-agent started
+
+	processes map[processKey]processCPU // cpu seconds of every matching process
+}
+
+// processKey identifies a process, the start time tells a reused pid apart
+type processKey struct {
+	pid       string
+	startTime uint64
+}
+
+// processCPU user and system cpu seconds of a process
+type processCPU struct {
+	user   float64
+	system float64
+}
+
+// processGroupCPU the processes of a group seen by the previous gather and the cpu seconds of those that exited
+type processGroupCPU struct {
+	processes map[processKey]processCPU
+	exited    processCPU
+}
+
+// procPIDStat fields of /proc/[pid]/stat
+type procPIDStat struct {
+	Comm      string
+	UTime     uint64
+	STime     uint64
+	Threads   uint64
+	StartTime uint64 // clock ticks after boot
+	RSSPages  uint64
+}
+
+// processMatcher a compiled processes.groups entry
+type processMatcher struct {
+	name    string
+	comm    *regexp.Regexp
+	cmdline *regexp.Regexp
+}
+
+var (
+	prevProcessGroupStart    = make(map[string]float64)
+	processGroupRestartCount = make(map[string]uint64)
+	processGroupCPUs         = make(map[string]*processGroupCPU)
+)
+
+// getProcessGroupStats groups /proc/[pid] by the configured processes.groups
+func getProcessGroupStats() ([]*ProcessGroupStats, error) {
+	procStat, err := getProcStat()
+	if err != nil {
+		return nil, err
+	}
+
+	matchers, err := newProcessMatchers(config.GetProcessGroups())
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := groupProcesses(procPath, matchers, procStat.BootTime)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range stats {
+		countProcessGroupRestart(stats[i])
+		accumulateProcessGroupCPU(stats[i])
+	}
+
+	return stats, nil
+}
+
+func newProcessMatchers(groups []config.ProcessGroup) ([]*processMatcher, error) {
+	var matchers []*processMatcher
+
+	for i := range groups {
+		m := processMatcher{name: groups[i].Name}
+
+		if groups[i].Comm != "" {
+			re, err := regexp.Compile(groups[i].Comm)
+			if err != nil {
+				return nil, err
+			}
+
+			m.comm = re
+		}
+
+		if groups[i].Cmdline != "" {
+			re, err := regexp.Compile(groups[i].Cmdline)
+			if err != nil {
+				return nil, err
+			}
+
+			m.cmdline = re
+		}
+
+		matchers = append(matchers, &m)
+	}
+
+	return matchers, nil
+}
+
+// match returns true if both comm and cmdline match, unset regexes always match
+func (m *processMatcher) match(comm, cmdline string) bool {
+	if m.comm != nil && !m.comm.MatchString(comm) {
+		return false
+	}
+
+	if m.cmdline != nil && !m.cmdline.MatchString(cmdline) {
+		return false
+	}
+
+	return true
+}
+
+// countProcessGroupRestart counts a restart when the oldest process of the group changes (restarted, or started after being down)
+func countProcessGroupRestart(s *ProcessGroupStats) {
+	prev, ok := prevProcessGroupStart[s.Group]
+
+	if ok && s.StartTime != 0 && s.StartTime != prev {
+		processGroupRestartCount[s.Group]++
+	}
+
+	prevProcessGroupStart[s.Group] = s.StartTime
+	s.Restarts = processGroupRestartCount[s.Group]
+}
+
+// accumulateProcessGroupCPU adds the cpu seconds of processes that exited since the previous gather, so the group's cpu
+// time only goes down when v-agent restarts instead of every time a process exits
+//
+// the cpu time a process used between the last gather and its exit is not counted
+func accumulateProcessGroupCPU(s *ProcessGroupStats) {
+	c, ok := processGroupCPUs[s.Group]
+	if !ok {
+		c = &processGroupCPU{}
+		processGroupCPUs[s.Group] = c
+	}
+
+	for key, cpu := range c.processes {
+		if _, ok := s.processes[key]; !ok {
+			c.exited.user += cpu.user
+			c.exited.system += cpu.system
+		}
+	}
+
+	c.processes = s.processes
+
+	s.CPUUserSeconds += c.exited.user
+	s.CPUSystemSeconds += c.exited.system
+}
+
+// groupProcesses walks root (/proc), every group is returned even if no process matches
+func groupProcesses(root string, matchers []*processMatcher, bootTime uint64) ([]*ProcessGroupStats, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*ProcessGroupStats, len(matchers))
+	for i := range matchers {
+		stats[i] = &ProcessGroupStats{Group: matchers[i].name, processes: make(map[processKey]processCPU)}
+	}
+
+	pageSize := uint64(os.Getpagesize())
+
+	for i := range entries {
+		if _, err := strconv.ParseUint(entries[i].Name(), 10, 64); err != nil {
+			continue
+		}
+
+		dir := filepath.Join(root, entries[i].Name())
+
+		// processes can exit while being read, skip them
+		data, err := os.ReadFile(filepath.Join(dir, "stat")) //nolint
+		if err != nil {
+			continue
+		}
+
+		stat, err := parseProcPIDStat(string(data))
+		if err != nil {
+			continue
+		}
+
+		cmdline, err := os.ReadFile(filepath.Join(dir, "cmdline")) //nolint
+		if err != nil {
+			continue
+		}
+
+		args := strings.TrimSpace(string(bytes.ReplaceAll(cmdline, []byte{0}, []byte{' '})))
+
+		var fds uint64
+		var fdsRead bool
+
+		for j := range matchers {
+			if !matchers[j].match(stat.Comm, args) {
+				continue
+			}
+
+			// requires CAP_SYS_PTRACE for other users' processes, only read when needed
+			if !fdsRead {
+				if f, err := os.ReadDir(filepath.Join(dir, "fd")); err == nil {
+					fds = uint64(len(f))
+				}
+
+				fdsRead = true
+			}
+
+			s := stats[j]
+			s.Count++
+			s.Threads += stat.Threads
+			s.RSSBytes += stat.RSSPages * pageSize
+			s.OpenFDs += fds
+			cpu := processCPU{user: float64(stat.UTime) / userHZ, system: float64(stat.STime) / userHZ}
+			s.processes[processKey{pid: entries[i].Name(), startTime: stat.StartTime}] = cpu
+			s.CPUUserSeconds += cpu.user
+			s.CPUSystemSeconds += cpu.system
+
+			startTime := float64(bootTime) + float64(stat.StartTime)/userHZ
+			if s.StartTime == 0 || startTime < s.StartTime {
+				s.StartTime = startTime
+			}
+		}
+	}
+
+	return stats, nil
+}
+
+// parseProcPIDStat parses /proc/[pid]/stat, comm is in parentheses and can contain spaces and parentheses:
+//
+//	1234 (nginx: worker) S 1 1234 1234 0 -1 4194624 ...
+//
+// https://man7.org/linux/man-pages/man5/proc.5.html
+func parseProcPIDStat(data string) (*procPIDStat, error) {
+	start := strings.IndexByte(data, '(')
+	end := strings.LastIndexByte(data, ')')
+
+	if start == -1 || end < start {
+		return nil, errors.New("malformed stat, missing comm")
+	}
+
+	// fields after comm, starting at state (field 3)
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 { //nolint
+		return nil, fmt.Errorf("malformed stat, %d fields after comm", len(fields))
+	}
+
+	stat := procPIDStat{Comm: data[start+1 : end]}
+
+	for _, f := range []struct {
+		index int
+		value *uint64
+	}{
+		{11, &stat.UTime},     // field 14
+		{12, &stat.STime},     // field 15
+		{17, &stat.Threads},   // field 20
+		{19, &stat.StartTime}, // field 22
+		{21, &stat.RSSPages},  // field 24
+	} {
+		v, err := strconv.ParseUint(fields[f.index], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		*f.value = v
+	}
+
+	return &stat, nil
+}
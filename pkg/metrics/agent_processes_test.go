@@ -0,0 +1,121 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"os"
+	"testing"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+)
+
+func TestParseProcPIDStat(t *testing.T) {
+	stat, err := parseProcPIDStat("1234 (nginx: worker (1)) S 1 1234 1234 0 -1 4194624 2000 0 0 0 250 120 0 0 20 0 4 0 360000 123456789 2048 18446744073709551615 1 1 0 0 0 0 0 0 0 0 0 0 17 1 0 0 0 0 0\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := procPIDStat{Comm: "nginx: worker (1)", UTime: 250, STime: 120, Threads: 4, StartTime: 360000, RSSPages: 2048}
+	if *stat != expected {
+		t.Errorf("expected %+v, got %+v", expected, *stat)
+	}
+
+	if _, err := parseProcPIDStat("1234 nginx S 1"); err == nil {
+		t.Error("expected error for stat without comm")
+	}
+}
+
+func TestGroupProcesses(t *testing.T) {
+	root := t.TempDir()
+
+	stat := func(pid, comm, utime, threads, start, rss string) string {
+		return pid + " (" + comm + ") S 1 1 1 0 -1 0 0 0 0 0 " + utime + " 100 0 0 20 0 " + threads + " 0 " + start + " 0 " + rss + " 0"
+	}
+
+	writeSysFiles(t, root, map[string]string{
+		"100/stat":    stat("100", "haproxy", "500", "1", "1000", "256"),
+		"100/cmdline": "/usr/sbin/haproxy\x00-f\x00/etc/haproxy/haproxy.cfg",
+		"101/stat":    stat("101", "haproxy", "300", "8", "2000", "1024"),
+		"101/cmdline": "/usr/sbin/haproxy\x00-f\x00/etc/haproxy/haproxy.cfg",
+		"200/stat":    stat("200", "ceph-osd", "100", "60", "3000", "4096"),
+		"200/cmdline": "/usr/bin/ceph-osd\x00--id\x003",
+		"self/stat":   stat("1", "v-agent", "1", "1", "1", "1"),
+	})
+
+	if err := os.MkdirAll(root+"/100/fd/0", 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	matchers, err := newProcessMatchers([]config.ProcessGroup{
+		{Name: "haproxy", Comm: "^haproxy$"},
+		{Name: "ceph-osd-3", Cmdline: `^/usr/bin/ceph-osd --id 3$`},
+		{Name: "kubelet", Comm: "^kubelet$"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := groupProcesses(root, matchers, 1700000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 groups, got %d", len(stats))
+	}
+
+	haproxy := stats[0]
+	if haproxy.Count != 2 || haproxy.Threads != 9 || haproxy.OpenFDs != 1 || haproxy.CPUUserSeconds != 8 || haproxy.CPUSystemSeconds != 2 {
+		t.Errorf("unexpected haproxy %+v", haproxy)
+	}
+
+	if haproxy.RSSBytes != 1280*uint64(os.Getpagesize()) || haproxy.StartTime != 1700000010 {
+		t.Errorf("unexpected haproxy %+v", haproxy)
+	}
+
+	if stats[1].Count != 1 || stats[1].Threads != 60 {
+		t.Errorf("unexpected ceph-osd %+v", stats[1])
+	}
+
+	if stats[2].Group != "kubelet" || stats[2].Count != 0 || stats[2].StartTime != 0 {
+		t.Errorf("unexpected kubelet %+v", stats[2])
+	}
+}
+
+func TestCountProcessGroupRestart(t *testing.T) {
+	for i, startTime := range []float64{100, 100, 0, 200, 200, 300} {
+		s := ProcessGroupStats{Group: "test-restarts", StartTime: startTime}
+
+		countProcessGroupRestart(&s)
+
+		expected := []uint64{0, 0, 0, 1, 1, 2}[i]
+		if s.Restarts != expected {
+			t.Errorf("gather %d: expected %d restarts, got %d", i, expected, s.Restarts)
+		}
+	}
+}
+
+func TestAccumulateProcessGroupCPU(t *testing.T) {
+	gathers := []map[processKey]processCPU{
+		{{"100", 1}: {user: 5, system: 1}, {"101", 1}: {user: 3, system: 1}},
+		{{"100", 1}: {user: 6, system: 1}},                                   // 101 exited
+		{{"100", 1}: {user: 7, system: 2}, {"101", 9}: {user: 1, system: 0}}, // pid 101 reused
+		{}, // everything exited
+	}
+
+	expected := []processCPU{{user: 8, system: 2}, {user: 9, system: 2}, {user: 11, system: 3}, {user: 11, system: 3}}
+
+	for i, processes := range gathers {
+		s := ProcessGroupStats{Group: "test-cpu", processes: processes}
+
+		for _, cpu := range processes {
+			s.CPUUserSeconds += cpu.user
+			s.CPUSystemSeconds += cpu.system
+		}
+
+		accumulateProcessGroupCPU(&s)
+
+		if s.CPUUserSeconds != expected[i].user || s.CPUSystemSeconds != expected[i].system {
+			t.Errorf("gather %d: expected %+v, got user %v system %v", i, expected[i], s.CPUUserSeconds, s.CPUSystemSeconds)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+
+	"go.uber.org/zap"
+)
+
+const procPressurePath = "/proc/pressure"
+
+// psiResources resources exposed by the kernel under /proc/pressure and per cgroup (<resource>.pressure)
+//
+// irq requires CONFIG_IRQ_TIME_ACCOUNTING and only has a "full" line
+var psiResources = []string{"cpu", "memory", "io", "irq"}
+
+// PressureStats a single line from a PSI file
+//
+// https://docs.kernel.org/accounting/psi.html
+type PressureStats struct {
+	Cgroup   string
+	Resource string
+	Type     string // some or full
+	Avg10    float64
+	Avg60    float64
+	Avg300   float64
+	Total    uint64 // microseconds
+}
+
+// getPSI reads /proc/pressure/{cpu,memory,io,irq}
+func getPSI() ([]*PressureStats, error) {
+	_, err := os.Stat(procPressurePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return getPressureFiles(procPressurePath, "", "%s")
+}
+
+// getCgroupPSI reads <resource>.pressure for a cgroup v2 path, relative paths are relative to cgroups.root
+func getCgroupPSI(cgroup string) ([]*PressureStats, error) {
+	dir := cgroup
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(config.GetCgroupRoot(), cgroup)
+	}
+
+	_, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return getPressureFiles(dir, cgroup, "%s.pressure")
+}
+
+func getPressureFiles(dir, cgroup, nameFmt string) ([]*PressureStats, error) {
+	log := zap.L().Sugar()
+
+	var stats []*PressureStats
+
+	for _, resource := range psiResources {
+		path := filepath.Join(dir, fmt.Sprintf(nameFmt, resource))
+
+		fd, err := os.Open(path) //nolint
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				log.Debugf("psi: %s does not exist, skipping", path)
+
+				continue
+			}
+
+			return nil, err
+		}
+
+		ps, err := parsePressure(resource, fd)
+		fd.Close() //nolint
+		if err != nil {
+			// psi can be disabled at runtime per cgroup (cgroup.pressure = 0), reads then fail with EOPNOTSUPP
+			log.Debugf("psi: unable to read %s: %s", path, err)
+
+			continue
+		}
+
+		for i := range ps {
+			ps[i].Cgroup = cgroup
+		}
+
+		stats = append(stats, ps...)
+	}
+
+	return stats, nil
+}
+
+// parsePressure parses a PSI file:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(resource string, r io.Reader) ([]*PressureStats, error) {
+	reader := bufio.NewReader(r)
+
+	var stats []*PressureStats
+
+	for {
+		data, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		splitted := strings.Fields(data)
+
+		if len(splitted) == 5 { //nolint
+			ps := PressureStats{
+				Resource: resource,
+				Type:     splitted[0],
+			}
+
+			for _, kv := range splitted[1:] {
+				k, v, ok := strings.Cut(kv, "=")
+				if !ok {
+					return nil, fmt.Errorf("psi: malformed field %q in %s", kv, resource)
+				}
+
+				var errParse error
+
+				switch k {
+				case "avg10":
+					ps.Avg10, errParse = strconv.ParseFloat(v, 64)
+				case "avg60":
+					ps.Avg60, errParse = strconv.ParseFloat(v, 64)
+				case "avg300":
+					ps.Avg300, errParse = strconv.ParseFloat(v, 64)
+				case "total":
+					ps.Total, errParse = strconv.ParseUint(v, 10, 64)
+				}
+
+				if errParse != nil {
+					return nil, errParse
+				}
+			}
+
+			stats = append(stats, &ps)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,39 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestGetPSI(t *testing.T) {
+	_, err := getPSI()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		t.Error(err)
+	}
+}
+
+func TestParsePressure(t *testing.T) {
+	data := `some avg10=1.50 avg60=0.25 avg300=0.05 total=123456
+full avg10=0.00 avg60=0.00 avg300=0.00 total=42
+`
+
+	ps, err := parsePressure("memory", strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ps) != 2 {
+		t.Fatalf("expect 2 lines, got %d", len(ps))
+	}
+
+	if ps[0].Resource != "memory" || ps[0].Type != "some" || ps[0].Avg10 != 1.5 || ps[0].Avg60 != 0.25 || ps[0].Avg300 != 0.05 || ps[0].Total != 123456 {
+		t.Errorf("unexpected some: %+v", ps[0])
+	}
+
+	if ps[1].Type != "full" || ps[1].Total != 42 {
+		t.Errorf("unexpected full: %+v", ps[1])
+	}
+}
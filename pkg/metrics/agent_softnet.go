@@ -0,0 +1,152 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SoftnetStats per cpu backlog statistics from /proc/net/softnet_stat
+//
+// https://github.com/torvalds/linux/blob/master/net/core/net-procfs.c (softnet_seq_show)
+type SoftnetStats struct {
+	CPU            string
+	Processed      uint64
+	Dropped        uint64
+	TimeSqueeze    uint64
+	ReceivedRPS    uint64
+	FlowLimitCount uint64
+	BacklogLen     uint64
+}
+
+// getSoftnetStats reads /proc/net/softnet_stat
+func getSoftnetStats() ([]*SoftnetStats, error) {
+	_, err := os.Stat("/proc/net/softnet_stat")
+	if err != nil {
+		return nil, err
+	}
+
+	softnetFD, err := os.Open("/proc/net/softnet_stat")
+	if err != nil {
+		return nil, err
+	}
+	defer softnetFD.Close() //nolint
+
+	// without the online cpus the line number is used, only wrong with offline cpus on kernels < 5.10
+	var online []int
+
+	if s, err := readSysString(filepath.Join(sysDevicesCPUPath, "online")); err == nil {
+		online, _ = parseCPUList(s)
+	}
+
+	return parseSoftnetStats(softnetFD, online)
+}
+
+// parseSoftnetStats parses /proc/net/softnet_stat, one line of hex values per online cpu
+//
+// columns were appended over time: received_rps (10) since 2.6.35, flow_limit_count (11) since 3.11, backlog_len and the
+// cpu index (12, 13) since 5.10. Older kernels skip offline cpus so line N is the Nth online cpu
+func parseSoftnetStats(r io.Reader, online []int) ([]*SoftnetStats, error) {
+	reader := bufio.NewReader(r)
+
+	var stats []*SoftnetStats
+
+	for line := 0; ; line++ {
+		data, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		splitted := strings.Fields(data)
+
+		if len(splitted) > 0 {
+			if len(splitted) < 3 { //nolint
+				return nil, fmt.Errorf("softnet: expecting at least 3 fields, got %d", len(splitted))
+			}
+
+			values := make([]uint64, len(splitted))
+			for i := range splitted {
+				val, errParse := strconv.ParseUint(splitted[i], 16, 64)
+				if errParse != nil {
+					return nil, errParse
+				}
+
+				values[i] = val
+			}
+
+			cpu := line
+			if line < len(online) {
+				cpu = online[line]
+			}
+
+			ss := SoftnetStats{
+				CPU:         fmt.Sprintf("cpu%d", cpu),
+				Processed:   values[0],
+				Dropped:     values[1],
+				TimeSqueeze: values[2],
+			}
+
+			if len(values) >= 10 { //nolint
+				ss.ReceivedRPS = values[9]
+			}
+
+			if len(values) >= 11 { //nolint
+				ss.FlowLimitCount = values[10]
+			}
+
+			if len(values) >= 13 { //nolint
+				ss.BacklogLen = values[11]
+				ss.CPU = fmt.Sprintf("cpu%d", values[12])
+			}
+
+			stats = append(stats, &ss)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return stats, nil
+}
+
+// parseCPUList parses a kernel cpu list (/sys/devices/system/cpu/online), "0-3,8,10-11"
+func parseCPUList(s string) ([]int, error) {
+	var cpus []int
+
+	for _, r := range strings.Split(strings.TrimSpace(s), ",") {
+		if r == "" {
+			continue
+		}
+
+		first, last, isRange := strings.Cut(r, "-")
+
+		start, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, err
+		}
+
+		end := start
+
+		if isRange {
+			if end, err = strconv.Atoi(last); err != nil {
+				return nil, err
+			}
+		}
+
+		if end < start {
+			return nil, fmt.Errorf("invalid cpu range %q", r)
+		}
+
+		for cpu := start; cpu <= end; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+
+	return cpus, nil
+}
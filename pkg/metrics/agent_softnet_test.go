@@ -0,0 +1,86 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestGetSoftnetStats(t *testing.T) {
+	_, err := getSoftnetStats()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseSoftnetStats(t *testing.T) {
+	data := `00000edb 00000002 00000010 00000000 00000000 00000000 00000000 00000000 00000000 00000005 00000001
+0001e240 00000000 00000003 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000004 00000003
+`
+
+	stats, err := parseSoftnetStats(strings.NewReader(data), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 2 {
+		t.Fatalf("expect 2 cpus, got %d", len(stats))
+	}
+
+	if stats[0].CPU != "cpu0" || stats[0].Processed != 0xedb || stats[0].Dropped != 2 || stats[0].TimeSqueeze != 0x10 || stats[0].ReceivedRPS != 5 || stats[0].FlowLimitCount != 1 {
+		t.Errorf("unexpected first line: %+v", stats[0])
+	}
+
+	// cpu index from column 13
+	if stats[1].CPU != "cpu3" || stats[1].Processed != 0x1e240 || stats[1].BacklogLen != 4 {
+		t.Errorf("unexpected second line: %+v", stats[1])
+	}
+}
+
+func TestParseSoftnetStatsOfflineCPUs(t *testing.T) {
+	// kernel 3.10 with cpu 1 offline, no flow_limit_count or cpu index column
+	data := `00000001 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000007
+00000002 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000
+00000003 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000 00000000
+`
+
+	online, err := parseCPUList("0,2-3\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := parseSoftnetStats(strings.NewReader(data), online)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(stats) != 3 || stats[0].CPU != "cpu0" || stats[1].CPU != "cpu2" || stats[2].CPU != "cpu3" {
+		t.Errorf("unexpected cpus %+v", stats)
+	}
+
+	if stats[0].Processed != 1 || stats[0].ReceivedRPS != 7 || stats[0].FlowLimitCount != 0 {
+		t.Errorf("unexpected first line: %+v", stats[0])
+	}
+
+	if _, err := parseSoftnetStats(strings.NewReader("00000001 00000000\n"), nil); err == nil {
+		t.Error("expected error for a line with 2 fields")
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := parseCPUList("0-2,5,8-9")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if fmt.Sprint(cpus) != "[0 1 2 5 8 9]" {
+		t.Errorf("unexpected cpus %v", cpus)
+	}
+
+	for _, s := range []string{"a", "3-1", "1-b"} {
+		if _, err := parseCPUList(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	systemdPrivateSocket = "unix:path=/run/systemd/private"
+	systemdTimeout       = 10 * time.Second
+)
+
+// systemdActiveStates possible ActiveState values, every state is reported so alerts can match on state="failed" == 1
+var systemdActiveStates = []string{"active", "reloading", "inactive", "failed", "activating", "deactivating"}
+
+// SystemdUnitStats state of a systemd unit
+type SystemdUnitStats struct {
+	Unit        string
+	LoadState   string // loaded, not-found, masked, etc
+	ActiveState string
+	SubState    string
+
+	ActiveEnterTime float64 // unix seconds, 0 if never active
+	Restarts        uint32  // services only
+	HasRestarts     bool
+	LastTrigger     float64 // unix seconds, timers only, 0 if never triggered
+	HasLastTrigger  bool
+}
+
+// systemdClient the systemd calls made by the collector, a systemdConn in production and a fake in tests
+type systemdClient interface {
+	loadUnit(ctx context.Context, unit string) (dbus.ObjectPath, error)
+	getProperty(ctx context.Context, path dbus.ObjectPath, iface, property string) (dbus.Variant, error)
+}
+
+// systemdConn a systemdClient over a D-Bus connection to systemd
+type systemdConn struct {
+	conn *dbus.Conn
+}
+
+// getSystemdUnits connects to systemd over its private socket, falling back to the system bus
+func getSystemdUnits(units []string) ([]*SystemdUnitStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), systemdTimeout)
+	defer cancel()
+
+	conn, err := dialSystemd(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close() //nolint
+
+	return getSystemdUnitStats(ctx, &systemdConn{conn: conn}, units)
+}
+
+// dialSystemd connects to /run/systemd/private (root only, works without dbus-daemon) or the system bus, the
+// connection is closed when ctx is done
+func dialSystemd(ctx context.Context) (*dbus.Conn, error) {
+	log := zap.L().Sugar()
+
+	conn, err := dbus.Dial(systemdPrivateSocket, dbus.WithContext(ctx))
+	if err == nil {
+		// a peer to peer connection, no Hello
+		if err = conn.Auth([]dbus.Auth{dbus.AuthExternal(strconv.Itoa(os.Geteuid()))}); err == nil {
+			return conn, nil
+		}
+
+		conn.Close() //nolint
+	}
+
+	log.Debugf("systemd: %s: %s, falling back to the system bus", systemdPrivateSocket, err)
+
+	return dbus.ConnectSystemBus(dbus.WithContext(ctx))
+}
+
+func (c *systemdConn) loadUnit(ctx context.Context, unit string) (dbus.ObjectPath, error) {
+	var path dbus.ObjectPath
+
+	err := c.conn.Object("org.freedesktop.systemd1", "/org/freedesktop/systemd1").
+		CallWithContext(ctx, "org.freedesktop.systemd1.Manager.LoadUnit", 0, unit).
+		Store(&path)
+
+	return path, err
+}
+
+func (c *systemdConn) getProperty(ctx context.Context, path dbus.ObjectPath, iface, property string) (dbus.Variant, error) {
+	var v dbus.Variant
+
+	err := c.conn.Object("org.freedesktop.systemd1", path).
+		CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, iface, property).
+		Store(&v)
+
+	return v, err
+}
+
+// getSystemdUnitStats returns the state of units, names without a suffix are treated as services
+func getSystemdUnitStats(ctx context.Context, client systemdClient, units []string) ([]*SystemdUnitStats, error) {
+	var stats []*SystemdUnitStats
+
+	for _, unit := range units {
+		if !strings.Contains(unit, ".") {
+			unit += ".service"
+		}
+
+		// LoadUnit returns a path for units that don't exist, LoadState is then not-found
+		path, err := client.loadUnit(ctx, unit)
+		if err != nil {
+			return nil, err
+		}
+
+		us := SystemdUnitStats{Unit: unit}
+
+		for property, dest := range map[string]*string{
+			"LoadState":   &us.LoadState,
+			"ActiveState": &us.ActiveState,
+			"SubState":    &us.SubState,
+		} {
+			v, err := client.getProperty(ctx, path, "org.freedesktop.systemd1.Unit", property)
+			if err != nil {
+				return nil, err
+			}
+
+			*dest, _ = v.Value().(string)
+		}
+
+		v, err := client.getProperty(ctx, path, "org.freedesktop.systemd1.Unit", "ActiveEnterTimestamp")
+		if err != nil {
+			return nil, err
+		}
+
+		us.ActiveEnterTime = systemdTimestamp(v.Value())
+
+		switch {
+		case strings.HasSuffix(unit, ".service"):
+			// NRestarts was added in systemd 235
+			if v, err := client.getProperty(ctx, path, "org.freedesktop.systemd1.Service", "NRestarts"); err == nil {
+				us.Restarts, us.HasRestarts = v.Value().(uint32)
+			}
+		case strings.HasSuffix(unit, ".timer"):
+			if v, err := client.getProperty(ctx, path, "org.freedesktop.systemd1.Timer", "LastTriggerUSec"); err == nil {
+				us.LastTrigger = systemdTimestamp(v.Value())
+				us.HasLastTrigger = true
+			}
+		}
+
+		stats = append(stats, &us)
+	}
+
+	return stats, nil
+}
+
+// systemdTimestamp converts a realtime usec timestamp to unix seconds
+func systemdTimestamp(v interface{}) float64 {
+	usec, ok := v.(uint64)
+	if !ok {
+		return 0
+	}
+
+	return float64(usec) / float64(time.Second/time.Microsecond)
+}
+
+// systemdAvailable returns false if systemd isn't pid 1 (containers, non systemd distributions)
+func systemdAvailable() bool {
+	_, err := os.Stat("/run/systemd/system")
+
+	return !errors.Is(err, os.ErrNotExist)
+}
@@ -0,0 +1,88 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakeSystemd answers LoadUnit and Properties.Get like systemd
+type fakeSystemd struct {
+	properties map[dbus.ObjectPath]map[string]dbus.Variant
+}
+
+func (f *fakeSystemd) loadUnit(_ context.Context, unit string) (dbus.ObjectPath, error) {
+	return dbus.ObjectPath("/org/freedesktop/systemd1/unit/" + strings.NewReplacer(".", "_2e", "-", "_2d").Replace(unit)), nil
+}
+
+func (f *fakeSystemd) getProperty(_ context.Context, path dbus.ObjectPath, _, property string) (dbus.Variant, error) {
+	v, ok := f.properties[path][property]
+	if !ok {
+		return dbus.Variant{}, dbus.Error{Name: "org.freedesktop.DBus.Error.UnknownProperty", Body: []interface{}{"Unknown property"}}
+	}
+
+	return v, nil
+}
+
+func TestGetSystemdUnitStats(t *testing.T) {
+	s := func(v string) dbus.Variant { return dbus.MakeVariant(v) }
+	u32 := func(v uint32) dbus.Variant { return dbus.MakeVariant(v) }
+	u64 := func(v uint64) dbus.Variant { return dbus.MakeVariant(v) }
+
+	properties := map[dbus.ObjectPath]map[string]dbus.Variant{
+		"/org/freedesktop/systemd1/unit/nginx_2eservice": {
+			"LoadState":            s("loaded"),
+			"ActiveState":          s("failed"),
+			"SubState":             s("failed"),
+			"ActiveEnterTimestamp": u64(1700000000500000),
+			"NRestarts":            u32(3),
+		},
+		// systemd < 235, no NRestarts
+		"/org/freedesktop/systemd1/unit/missing_2eservice": {
+			"LoadState":            s("not-found"),
+			"ActiveState":          s("inactive"),
+			"SubState":             s("dead"),
+			"ActiveEnterTimestamp": u64(0),
+		},
+		"/org/freedesktop/systemd1/unit/logrotate_2etimer": {
+			"LoadState":            s("loaded"),
+			"ActiveState":          s("active"),
+			"SubState":             s("waiting"),
+			"ActiveEnterTimestamp": u64(1600000000000000),
+			"LastTriggerUSec":      u64(1700000100000000),
+		},
+	}
+
+	client := &fakeSystemd{properties: properties}
+
+	units, err := getSystemdUnitStats(context.Background(), client, []string{"nginx", "missing.service", "logrotate.timer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(units) != 3 {
+		t.Fatalf("expected 3 units, got %d", len(units))
+	}
+
+	if u := units[0]; u.Unit != "nginx.service" || u.LoadState != "loaded" || u.ActiveState != "failed" || u.SubState != "failed" || u.ActiveEnterTime != 1700000000.5 || !u.HasRestarts || u.Restarts != 3 || u.HasLastTrigger {
+		t.Errorf("unexpected nginx.service %+v", u)
+	}
+
+	if u := units[1]; u.Unit != "missing.service" || u.LoadState != "not-found" || u.ActiveEnterTime != 0 || u.HasRestarts {
+		t.Errorf("unexpected missing.service %+v", u)
+	}
+
+	if u := units[2]; u.Unit != "logrotate.timer" || u.SubState != "waiting" || !u.HasLastTrigger || u.LastTrigger != 1700000100 || u.HasRestarts {
+		t.Errorf("unexpected logrotate.timer %+v", u)
+	}
+
+	// errors other than missing optional properties are returned
+	delete(properties["/org/freedesktop/systemd1/unit/nginx_2eservice"], "SubState")
+
+	if _, err := getSystemdUnitStats(context.Background(), client, []string{"nginx"}); err == nil {
+		t.Error("expected error for a missing SubState")
+	}
+}
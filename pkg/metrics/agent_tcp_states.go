@@ -0,0 +1,285 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"go.uber.org/zap"
+)
+
+// sock_diag, not exposed by the syscall package
+//
+// https://man7.org/linux/man-pages/man7/sock_diag.7.html
+const (
+	netlinkSockDiag   = 4  // NETLINK_SOCK_DIAG
+	sockDiagByFamily  = 20 // SOCK_DIAG_BY_FAMILY
+	inetDiagReqV2Len  = 56 // sizeof(struct inet_diag_req_v2)
+	inetDiagMsgMinLen = 72 // sizeof(struct inet_diag_msg)
+	tcpStatesAll      = 0xFFFFFFFF
+)
+
+// tcpStates maps the kernel tcp state (include/net/tcp_states.h) to a name
+var tcpStates = map[uint8]string{
+	1:  "ESTABLISHED",
+	2:  "SYN_SENT",
+	3:  "SYN_RECV",
+	4:  "FIN_WAIT1",
+	5:  "FIN_WAIT2",
+	6:  "TIME_WAIT",
+	7:  "CLOSE",
+	8:  "CLOSE_WAIT",
+	9:  "LAST_ACK",
+	10: "LISTEN",
+	11: "CLOSING",
+	12: "NEW_SYN_RECV",
+}
+
+// TCPSocket a single tcp socket
+type TCPSocket struct {
+	Family    string // ipv4 or ipv6
+	State     uint8
+	LocalPort uint16
+}
+
+// TCPStateCount count of tcp sockets in a state, Port is 0 when not broken down by port
+type TCPStateCount struct {
+	Family string
+	State  string
+	Port   uint16
+	Count  uint64
+}
+
+// getTCPStates counts tcp sockets by family and state, sockets with a local port in ports are also counted per port
+func getTCPStates(ports []uint16) ([]*TCPStateCount, error) {
+	sockets, err := getTCPSockets()
+	if err != nil {
+		return nil, err
+	}
+
+	return countTCPStates(sockets, ports), nil
+}
+
+func countTCPStates(sockets []TCPSocket, ports []uint16) []*TCPStateCount {
+	wanted := make(map[uint16]bool)
+	for i := range ports {
+		wanted[ports[i]] = true
+	}
+
+	counts := make(map[TCPStateCount]uint64)
+
+	for i := range sockets {
+		state, ok := tcpStates[sockets[i].State]
+		if !ok {
+			state = "UNKNOWN"
+		}
+
+		counts[TCPStateCount{Family: sockets[i].Family, State: state}]++
+
+		if wanted[sockets[i].LocalPort] {
+			counts[TCPStateCount{Family: sockets[i].Family, State: state, Port: sockets[i].LocalPort}]++
+		}
+	}
+
+	var stats []*TCPStateCount
+
+	for k, v := range counts {
+		k.Count = v
+		stats = append(stats, &k)
+	}
+
+	return stats
+}
+
+// getTCPSockets returns tcp sockets using netlink sock_diag, falls back to /proc/net/tcp{,6}
+func getTCPSockets() ([]TCPSocket, error) {
+	log := zap.L().Sugar()
+
+	var sockets []TCPSocket
+
+	for _, family := range []uint8{syscall.AF_INET, syscall.AF_INET6} {
+		s, err := getTCPSocketsNetlink(family)
+		if err != nil {
+			// sock_diag can be unavailable (seccomp, old kernel, missing inet_diag module)
+			log.Debugf("tcp_states: netlink sock_diag failed, falling back to /proc/net: %s", err)
+
+			return getTCPSocketsProc()
+		}
+
+		sockets = append(sockets, s...)
+	}
+
+	return sockets, nil
+}
+
+// getTCPSocketsNetlink dumps all tcp sockets for family using sock_diag
+func getTCPSocketsNetlink(family uint8) ([]TCPSocket, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, netlinkSockDiag)
+	if err != nil {
+		return nil, err
+	}
+	defer syscall.Close(fd) //nolint
+
+	if err := syscall.Bind(fd, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, err
+	}
+
+	// struct nlmsghdr followed by struct inet_diag_req_v2
+	req := make([]byte, syscall.NLMSG_HDRLEN+inetDiagReqV2Len)
+
+	hdr := (*syscall.NlMsghdr)(unsafe.Pointer(&req[0])) //nolint
+	hdr.Len = uint32(len(req))
+	hdr.Type = sockDiagByFamily
+	hdr.Flags = syscall.NLM_F_REQUEST | syscall.NLM_F_DUMP
+	hdr.Seq = 1
+
+	req[syscall.NLMSG_HDRLEN] = family
+	req[syscall.NLMSG_HDRLEN+1] = syscall.IPPROTO_TCP
+	binary.NativeEndian.PutUint32(req[syscall.NLMSG_HDRLEN+4:], tcpStatesAll) // idiag_states, host byte order
+
+	if err := syscall.Sendto(fd, req, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		return nil, err
+	}
+
+	var sockets []TCPSocket
+
+	buf := make([]byte, 32*1024) //nolint
+
+	for {
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs, err := syscall.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range msgs {
+			switch msgs[i].Header.Type {
+			case syscall.NLMSG_DONE:
+				return sockets, nil
+			case syscall.NLMSG_ERROR:
+				if len(msgs[i].Data) >= 4 { //nolint
+					errno := int32(binary.NativeEndian.Uint32(msgs[i].Data[:4])) // struct nlmsgerr, host byte order
+
+					return nil, fmt.Errorf("sock_diag: %w", syscall.Errno(-errno))
+				}
+
+				return nil, errors.New("sock_diag: netlink error")
+			}
+
+			// struct inet_diag_msg: family, state, timer, retrans, then struct inet_diag_sockid (sport is network byte order)
+			if len(msgs[i].Data) < inetDiagMsgMinLen {
+				continue
+			}
+
+			sockets = append(sockets, TCPSocket{
+				Family:    tcpFamilyName(msgs[i].Data[0]),
+				State:     msgs[i].Data[1],
+				LocalPort: binary.BigEndian.Uint16(msgs[i].Data[4:6]),
+			})
+		}
+	}
+}
+
+// getTCPSocketsProc reads /proc/net/tcp and /proc/net/tcp6
+func getTCPSocketsProc() ([]TCPSocket, error) {
+	var sockets []TCPSocket
+
+	for path, family := range map[string]uint8{"/proc/net/tcp": syscall.AF_INET, "/proc/net/tcp6": syscall.AF_INET6} {
+		fd, err := os.Open(path) //nolint
+		if err != nil {
+			// tcp6 is not present if ipv6 is disabled
+			if errors.Is(err, os.ErrNotExist) && family == syscall.AF_INET6 {
+				continue
+			}
+
+			return nil, err
+		}
+
+		s, err := parseProcNetTCP(family, fd)
+		fd.Close() //nolint
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		sockets = append(sockets, s...)
+	}
+
+	return sockets, nil
+}
+
+// parseProcNetTCP parses /proc/net/tcp{,6}:
+//
+//	sl  local_address rem_address   st tx_queue rx_queue ...
+//	0: 0100007F:0016 00000000:0000 0A 00000000:00000000 ...
+func parseProcNetTCP(family uint8, r io.Reader) ([]TCPSocket, error) {
+	reader := bufio.NewReader(r)
+
+	var sockets []TCPSocket
+
+	// header
+	if _, err := reader.ReadString('\n'); err != nil {
+		if err == io.EOF {
+			return sockets, nil
+		}
+
+		return nil, err
+	}
+
+	for {
+		data, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+
+		splitted := strings.Fields(data)
+
+		if len(splitted) >= 4 { //nolint
+			_, port, ok := strings.Cut(splitted[1], ":")
+			if !ok {
+				return nil, fmt.Errorf("malformed local_address %q", splitted[1])
+			}
+
+			localPort, errParse := strconv.ParseUint(port, 16, 16)
+			if errParse != nil {
+				return nil, errParse
+			}
+
+			state, errParse := strconv.ParseUint(splitted[3], 16, 8)
+			if errParse != nil {
+				return nil, errParse
+			}
+
+			sockets = append(sockets, TCPSocket{
+				Family:    tcpFamilyName(family),
+				State:     uint8(state),
+				LocalPort: uint16(localPort),
+			})
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return sockets, nil
+}
+
+func tcpFamilyName(family uint8) string {
+	if family == syscall.AF_INET6 {
+		return "ipv6"
+	}
+
+	return "ipv4"
+}
@@ -0,0 +1,68 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestGetTCPStates(t *testing.T) {
+	_, err := getTCPStates([]uint16{22})
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestGetTCPSocketsProc(t *testing.T) {
+	_, err := getTCPSocketsProc()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseProcNetTCP(t *testing.T) {
+	data := `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:0050 00000000:0000 0A 00000000:00000000 00:00000000 00000000     0        0 20101 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0050 0100007F:D431 08 00000000:00000000 00:00000000 00000000     0        0 20102 1 0000000000000000 20 4 30 10 -1
+   2: 0100007F:D431 0100007F:0050 01 00000000:00000000 00:00000000 00000000     0        0 20103 1 0000000000000000 20 4 30 10 -1
+`
+
+	sockets, err := parseProcNetTCP(syscall.AF_INET, strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(sockets) != 3 {
+		t.Fatalf("expect 3 sockets, got %d", len(sockets))
+	}
+
+	if sockets[0].Family != "ipv4" || sockets[0].State != 10 || sockets[0].LocalPort != 80 {
+		t.Errorf("unexpected listener: %+v", sockets[0])
+	}
+
+	counts := countTCPStates(sockets, []uint16{80})
+
+	got := make(map[TCPStateCount]uint64)
+	for i := range counts {
+		got[TCPStateCount{Family: counts[i].Family, State: counts[i].State, Port: counts[i].Port}] = counts[i].Count
+	}
+
+	expect := map[TCPStateCount]uint64{
+		{Family: "ipv4", State: "LISTEN"}:               1,
+		{Family: "ipv4", State: "CLOSE_WAIT"}:           1,
+		{Family: "ipv4", State: "ESTABLISHED"}:          1,
+		{Family: "ipv4", State: "LISTEN", Port: 80}:     1,
+		{Family: "ipv4", State: "CLOSE_WAIT", Port: 80}: 1,
+	}
+
+	if len(got) != len(expect) {
+		t.Errorf("unexpected counts: %v", got)
+	}
+
+	for k, v := range expect {
+		if got[k] != v {
+			t.Errorf("expect %+v to be %d, got %d", k, v, got[k])
+		}
+	}
+}
@@ -0,0 +1,93 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"syscall"
+	"time"
+)
+
+const (
+	timexStaUnsync = 0x0040 // STA_UNSYNC, set by the kernel when no time daemon has updated the clock for a while
+	timexStaNano   = 0x2000 // STA_NANO, offset and time are in nanoseconds instead of microseconds
+	timexTimeError = 5      // TIME_ERROR, clock not synchronized
+
+	// freq and ppsfreq are ppm with a 16 bit fractional part
+	timexPPM = 65536 * 1000000
+)
+
+// when the clock was first seen unsynchronized, zero while synchronized
+var timexUnsyncedSince time.Time
+
+// TimexStats kernel clock discipline state from adjtimex(2), works without chrony/ntpd being reachable
+//
+// https://man7.org/linux/man-pages/man2/adjtimex.2.html
+type TimexStats struct {
+	Offset       float64 // seconds
+	Frequency    float64 // ratio, 1 is no adjustment
+	MaxError     float64 // seconds, grows by 500us/s while no time daemon updates the clock
+	EstError     float64 // seconds
+	Status       float64 // STA_* bits
+	State        float64 // TIME_OK, TIME_INS, TIME_DEL, TIME_OOP, TIME_WAIT or TIME_ERROR
+	Synchronized bool
+	TAIOffset    float64 // seconds
+	TimeConstant float64
+	Tick         float64 // seconds
+	Time         float64 // unix seconds
+
+	// seconds since the clock was first seen unsynchronized by v-agent, 0 if synchronized
+	UnsynchronizedSeconds float64
+}
+
+// getTimexStats reads the clock state with adjtimex, modes 0 only reads and doesn't require CAP_SYS_TIME
+func getTimexStats() (*TimexStats, error) {
+	var tx syscall.Timex
+
+	state, err := syscall.Adjtimex(&tx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := newTimexStats(&tx, state)
+	stats.UnsynchronizedSeconds = unsynchronizedSeconds(stats.Synchronized, time.Now())
+
+	return stats, nil
+}
+
+func newTimexStats(tx *syscall.Timex, state int) *TimexStats {
+	// offset and the time's sub second part are in microseconds unless STA_NANO is set
+	divisor := float64(time.Second / time.Microsecond)
+	if tx.Status&timexStaNano != 0 {
+		divisor = float64(time.Second / time.Nanosecond)
+	}
+
+	us := float64(time.Second / time.Microsecond)
+
+	return &TimexStats{
+		Offset:       float64(tx.Offset) / divisor,
+		Frequency:    1 + float64(tx.Freq)/timexPPM,
+		MaxError:     float64(tx.Maxerror) / us,
+		EstError:     float64(tx.Esterror) / us,
+		Status:       float64(tx.Status),
+		State:        float64(state),
+		Synchronized: state != timexTimeError && tx.Status&timexStaUnsync == 0,
+		TAIOffset:    float64(tx.Tai),
+		TimeConstant: float64(tx.Constant),
+		Tick:         float64(tx.Tick) / us,
+		Time:         float64(tx.Time.Sec) + float64(tx.Time.Usec)/divisor,
+	}
+}
+
+// unsynchronizedSeconds tracks how long the clock has been unsynchronized across gathers
+func unsynchronizedSeconds(synchronized bool, now time.Time) float64 {
+	if synchronized {
+		timexUnsyncedSince = time.Time{}
+
+		return 0
+	}
+
+	if timexUnsyncedSince.IsZero() {
+		timexUnsyncedSince = now
+	}
+
+	return now.Sub(timexUnsyncedSince).Seconds()
+}
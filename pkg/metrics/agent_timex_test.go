@@ -0,0 +1,76 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNewTimexStats(t *testing.T) {
+	tx := syscall.Timex{
+		Offset:   -250000,
+		Freq:     -1310720, // -20ppm
+		Maxerror: 16000,
+		Esterror: 500,
+		Status:   0x2001, // STA_PLL | STA_NANO
+		Constant: 7,
+		Tick:     10000,
+		Tai:      37,
+		Time:     syscall.Timeval{Sec: 1700000000, Usec: 500000000},
+	}
+
+	stats := newTimexStats(&tx, 0)
+
+	if stats.Offset != -0.00025 || stats.Frequency != 0.99998 || stats.MaxError != 0.016 || stats.EstError != 0.0005 {
+		t.Errorf("unexpected offset/frequency/errors %+v", stats)
+	}
+
+	if !stats.Synchronized || stats.TAIOffset != 37 || stats.TimeConstant != 7 || stats.Tick != 0.01 || stats.Time != 1700000000.5 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// microsecond mode
+	tx.Status = 0x0001
+	tx.Time.Usec = 250000
+
+	if stats := newTimexStats(&tx, 0); stats.Offset != -0.25 || stats.Time != 1700000000.25 {
+		t.Errorf("unexpected microsecond offset/time %+v", stats)
+	}
+
+	tx.Status = 0x0041 // STA_PLL | STA_UNSYNC
+	if stats := newTimexStats(&tx, 0); stats.Synchronized {
+		t.Error("expected unsynchronized with STA_UNSYNC")
+	}
+
+	tx.Status = 0x0001
+	if stats := newTimexStats(&tx, timexTimeError); stats.Synchronized || stats.State != timexTimeError {
+		t.Errorf("expected unsynchronized with TIME_ERROR %+v", stats)
+	}
+}
+
+func TestUnsynchronizedSeconds(t *testing.T) {
+	defer func() { timexUnsyncedSince = time.Time{} }()
+
+	now := time.Unix(1700000000, 0)
+
+	if v := unsynchronizedSeconds(true, now); v != 0 {
+		t.Errorf("expected 0 while synchronized, got %f", v)
+	}
+
+	if v := unsynchronizedSeconds(false, now); v != 0 {
+		t.Errorf("expected 0 when first seen unsynchronized, got %f", v)
+	}
+
+	if v := unsynchronizedSeconds(false, now.Add(90*time.Second)); v != 90 {
+		t.Errorf("expected 90, got %f", v)
+	}
+
+	if v := unsynchronizedSeconds(true, now.Add(120*time.Second)); v != 0 {
+		t.Errorf("expected 0 after resynchronizing, got %f", v)
+	}
+
+	if v := unsynchronizedSeconds(false, now.Add(130*time.Second)); v != 0 {
+		t.Errorf("expected the count to restart, got %f", v)
+	}
+}
@@ -0,0 +1,76 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vultr/v-agent/cmd/v-agent/config"
+)
+
+// getVMStat reads /proc/vmstat, only fields matching vmstat.fields are returned
+func getVMStat() (map[string]uint64, error) {
+	_, err := os.Stat("/proc/vmstat")
+	if err != nil {
+		return nil, err
+	}
+
+	procVMStatFD, err := os.Open("/proc/vmstat")
+	if err != nil {
+		return nil, err
+	}
+	defer procVMStatFD.Close() //nolint
+
+	re, err := regexp.Compile(config.GetVMStatFields())
+	if err != nil {
+		return nil, err
+	}
+
+	vmstat, err := parseVMStat(procVMStatFD)
+	if err != nil {
+		return nil, err
+	}
+
+	for k := range vmstat {
+		if !re.MatchString(k) {
+			delete(vmstat, k)
+		}
+	}
+
+	return vmstat, nil
+}
+
+// parseVMStat parses the contents of /proc/vmstat (name value per line)
+func parseVMStat(r io.Reader) (map[string]uint64, error) {
+	reader := bufio.NewReader(r)
+
+	vmstat := make(map[string]uint64)
+
+	for {
+		data, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		if err == io.EOF {
+			break
+		}
+
+		splitted := strings.Fields(data)
+		if len(splitted) != 2 { //nolint
+			continue
+		}
+
+		val, err := strconv.ParseUint(splitted[1], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		vmstat[splitted[0]] = val
+	}
+
+	return vmstat, nil
+}
@@ -0,0 +1,38 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetVMStat(t *testing.T) {
+	vmstat, err := getVMStat()
+	if err != nil {
+		t.Error(err)
+	}
+
+	if _, ok := vmstat["pgfault"]; !ok {
+		t.Error("expect pgfault to match the default fields")
+	}
+
+	if _, ok := vmstat["nr_free_pages"]; ok {
+		t.Error("expect nr_free_pages to be filtered by the default fields")
+	}
+}
+
+func TestParseVMStat(t *testing.T) {
+	data := `nr_free_pages 2262395
+pgfault 1588735911
+oom_kill 3
+`
+
+	vmstat, err := parseVMStat(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(vmstat) != 3 || vmstat["pgfault"] != 1588735911 || vmstat["oom_kill"] != 3 {
+		t.Errorf("unexpected vmstat: %v", vmstat)
+	}
+}
@@ -28,6 +28,9 @@ var (
 	// ErrVCDNAgentServerUnhealthy returned if response is not status code 200 from /metrics
 	ErrVCDNAgentServerUnhealthy = errors.New("v-cdn-agent unhealthy")
 
+	// ErrStatfsTimeout returned if statfs on a mount doesn't return within file_system.statfs_timeout
+	ErrStatfsTimeout = errors.New("statfs timed out")
+
 	// ErrVDNSUnhealthy returned if response is not status code 200 from /metrics
 	ErrVDNSUnhealthy = errors.New("v-dns unhealthy")
 )
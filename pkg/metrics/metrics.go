@@ -5,6 +5,10 @@ import (
 	"bufio"
 	"bytes"
 	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,6 +23,7 @@ import (
 
 var (
 	vAgentVersion *prometheus.GaugeVec
+	vVendorCheck  *prometheus.GaugeVec
 
 	// load avg metrics
 	loadavgLoad1        *prometheus.GaugeVec
@@ -39,6 +44,19 @@ var (
 	cpuStealPct     *prometheus.GaugeVec
 	cpuGuestPct     *prometheus.GaugeVec
 	cpuGuestNicePct *prometheus.GaugeVec
+	cpuNicePct      *prometheus.GaugeVec
+
+	// cpu metrics: per cpu
+	cpuCoreUtilPct *prometheus.GaugeVec
+	cpuCoreModePct *prometheus.GaugeVec
+
+	// cpu metrics: /proc/stat counters
+	cpuContextSwitches *prometheus.GaugeVec
+	cpuInterrupts      *prometheus.GaugeVec
+	cpuForks           *prometheus.GaugeVec
+	cpuProcsRunning    *prometheus.GaugeVec
+	cpuProcsBlocked    *prometheus.GaugeVec
+	cpuBootTime        *prometheus.GaugeVec
 
 	// memory metrics
 	memoryTotal     *prometheus.GaugeVec
@@ -48,6 +66,12 @@ var (
 	memorySwapTotal *prometheus.GaugeVec
 	memorySwapFree  *prometheus.GaugeVec
 
+	// memory metrics: derived
+	memoryAvailablePct *prometheus.GaugeVec
+
+	// dynamically named metrics, registered on first sight (every field in /proc/meminfo, etc)
+	dynamicGauges = make(map[string]*prometheus.GaugeVec)
+
 	// nic metrics
 	nicBytes        *prometheus.GaugeVec
 	nicBytesTX      *prometheus.GaugeVec
@@ -88,14 +112,25 @@ var (
 	diskStatsDiscardsMerged         *prometheus.GaugeVec
 	diskStatsSectorsDiscarded       *prometheus.GaugeVec
 	diskStatsMillisecondsDiscarding *prometheus.GaugeVec
+	diskReadBytes                   *prometheus.GaugeVec
+	diskWriteBytes                  *prometheus.GaugeVec
+	diskReadIOPS                    *prometheus.GaugeVec
+	diskWriteIOPS                   *prometheus.GaugeVec
+	diskReadAwait                   *prometheus.GaugeVec
+	diskWriteAwait                  *prometheus.GaugeVec
+	diskQueueDepth                  *prometheus.GaugeVec
+	diskUtil                        *prometheus.GaugeVec
+	diskInfo                        *prometheus.GaugeVec
 
 	// filesystem metrics
-	fsInodes     *prometheus.GaugeVec
-	fsInodesUsed *prometheus.GaugeVec
-	fsInodesUtil *prometheus.GaugeVec
-	fsBytes      *prometheus.GaugeVec
-	fsBytesUsed  *prometheus.GaugeVec
-	fsBytesUtil  *prometheus.GaugeVec
+	fsInodes      *prometheus.GaugeVec
+	fsInodesUsed  *prometheus.GaugeVec
+	fsInodesUtil  *prometheus.GaugeVec
+	fsBytes       *prometheus.GaugeVec
+	fsBytesUsed   *prometheus.GaugeVec
+	fsBytesUtil   *prometheus.GaugeVec
+	fsReadOnly    *prometheus.GaugeVec
+	fsDeviceError *prometheus.GaugeVec
 
 	// kubernetes
 	kubeAPIServerHealthz *prometheus.GaugeVec
@@ -118,6 +153,184 @@ var (
 	// v-dns
 	vdnsHealthy *prometheus.GaugeVec
 
+	// psi
+	psiAvg10            *prometheus.GaugeVec
+	psiAvg60            *prometheus.GaugeVec
+	psiAvg300           *prometheus.GaugeVec
+	psiStallTotal       *prometheus.GaugeVec
+	psiCgroupAvg10      *prometheus.GaugeVec
+	psiCgroupAvg60      *prometheus.GaugeVec
+	psiCgroupAvg300     *prometheus.GaugeVec
+	psiCgroupStallTotal *prometheus.GaugeVec
+
+	// conntrack
+	conntrackEntries      *prometheus.GaugeVec
+	conntrackEntriesLimit *prometheus.GaugeVec
+
+	// softnet
+	softnetProcessed      *prometheus.GaugeVec
+	softnetDropped        *prometheus.GaugeVec
+	softnetTimeSqueeze    *prometheus.GaugeVec
+	softnetReceivedRPS    *prometheus.GaugeVec
+	softnetFlowLimitCount *prometheus.GaugeVec
+	softnetBacklogLen     *prometheus.GaugeVec
+
+	// tcp states
+	tcpConnections     *prometheus.GaugeVec
+	tcpConnectionsPort *prometheus.GaugeVec
+
+	// ethtool
+	nicLinkSpeed      *prometheus.GaugeVec
+	nicLinkFullDuplex *prometheus.GaugeVec
+	nicCarrier        *prometheus.GaugeVec
+	nicMTU            *prometheus.GaugeVec
+	nicOperState      *prometheus.GaugeVec
+	ethtoolInfo       *prometheus.GaugeVec
+
+	// hwmon
+	hwmonTemp              *prometheus.GaugeVec
+	hwmonTempCrit          *prometheus.GaugeVec
+	hwmonFan               *prometheus.GaugeVec
+	hwmonIn                *prometheus.GaugeVec
+	hwmonPower             *prometheus.GaugeVec
+	hwmonAlarm             *prometheus.GaugeVec
+	thermalZoneTemp        *prometheus.GaugeVec
+	thermalCoolingCurState *prometheus.GaugeVec
+	thermalCoolingMaxState *prometheus.GaugeVec
+
+	// cpufreq
+	cpuFreqCur             *prometheus.GaugeVec
+	cpuFreqMin             *prometheus.GaugeVec
+	cpuFreqMax             *prometheus.GaugeVec
+	cpuFreqGovernor        *prometheus.GaugeVec
+	cpuCoreThrottles       *prometheus.GaugeVec
+	cpuCoreThrottleTime    *prometheus.GaugeVec
+	cpuPackageThrottles    *prometheus.GaugeVec
+	cpuPackageThrottleTime *prometheus.GaugeVec
+
+	// mdadm
+	mdState         *prometheus.GaugeVec
+	mdDisks         *prometheus.GaugeVec
+	mdDisksRequired *prometheus.GaugeVec
+	mdDegraded      *prometheus.GaugeVec
+	mdSyncAction    *prometheus.GaugeVec
+	mdSyncProgress  *prometheus.GaugeVec
+	mdSyncFinish    *prometheus.GaugeVec
+	mdSyncSpeed     *prometheus.GaugeVec
+
+	// processes
+	processGroupCount     *prometheus.GaugeVec
+	processGroupRunning   *prometheus.GaugeVec
+	processGroupThreads   *prometheus.GaugeVec
+	processGroupRSS       *prometheus.GaugeVec
+	processGroupOpenFDs   *prometheus.GaugeVec
+	processGroupCPUUser   *prometheus.GaugeVec
+	processGroupCPUSystem *prometheus.GaugeVec
+	processGroupStartTime *prometheus.GaugeVec
+	processGroupRestarts  *prometheus.GaugeVec
+
+	// cgroups
+	cgroupCPUUsage            *prometheus.GaugeVec
+	cgroupCPUUser             *prometheus.GaugeVec
+	cgroupCPUSystem           *prometheus.GaugeVec
+	cgroupCPUPeriods          *prometheus.GaugeVec
+	cgroupCPUThrottledPeriods *prometheus.GaugeVec
+	cgroupCPUThrottled        *prometheus.GaugeVec
+	cgroupMemoryCurrent       *prometheus.GaugeVec
+	cgroupMemoryMax           *prometheus.GaugeVec
+	cgroupMemoryEvents        *prometheus.GaugeVec
+	cgroupIORead              *prometheus.GaugeVec
+	cgroupIOWrite             *prometheus.GaugeVec
+	cgroupIOReads             *prometheus.GaugeVec
+	cgroupIOWrites            *prometheus.GaugeVec
+	cgroupPidsCurrent         *prometheus.GaugeVec
+	cgroupPidsMax             *prometheus.GaugeVec
+
+	// systemd
+	systemdUnitState           *prometheus.GaugeVec
+	systemdUnitInfo            *prometheus.GaugeVec
+	systemdUnitActiveEnterTime *prometheus.GaugeVec
+	systemdUnitRestarts        *prometheus.GaugeVec
+	systemdTimerLastTrigger    *prometheus.GaugeVec
+
+	// timex
+	timexOffset         *prometheus.GaugeVec
+	timexFrequency      *prometheus.GaugeVec
+	timexMaxError       *prometheus.GaugeVec
+	timexEstError       *prometheus.GaugeVec
+	timexStatus         *prometheus.GaugeVec
+	timexState          *prometheus.GaugeVec
+	timexSyncStatus     *prometheus.GaugeVec
+	timexUnsynchronized *prometheus.GaugeVec
+	timexTAIOffset      *prometheus.GaugeVec
+	timexTimeConstant   *prometheus.GaugeVec
+	timexTick           *prometheus.GaugeVec
+	timexTime           *prometheus.GaugeVec
+
+	// node info
+	nodeUnameInfo     *prometheus.GaugeVec
+	nodeOSReleaseInfo *prometheus.GaugeVec
+	nodeDMIInfo       *prometheus.GaugeVec
+	nodeUptime        *prometheus.GaugeVec
+
+	// kernel limits
+	kernelFileAllocated       *prometheus.GaugeVec
+	kernelFileMax             *prometheus.GaugeVec
+	kernelInodeTotal          *prometheus.GaugeVec
+	kernelInodeFree           *prometheus.GaugeVec
+	kernelEntropyAvail        *prometheus.GaugeVec
+	kernelEntropyPoolSize     *prometheus.GaugeVec
+	kernelPIDMax              *prometheus.GaugeVec
+	kernelThreadsMax          *prometheus.GaugeVec
+	kernelThreads             *prometheus.GaugeVec
+	kernelInotifyMaxWatches   *prometheus.GaugeVec
+	kernelInotifyMaxInstances *prometheus.GaugeVec
+	kernelInotifyWatches      *prometheus.GaugeVec
+	kernelInotifyInstances    *prometheus.GaugeVec
+
+	// numa
+	numaHugePagesTotal   *prometheus.GaugeVec
+	numaHugePagesFree    *prometheus.GaugeVec
+	numaHugePagesSurplus *prometheus.GaugeVec
+
+	// kmsg
+	kmsgMatches *prometheus.GaugeVec
+	kmsgRecords *prometheus.GaugeVec
+	kmsgDropped *prometheus.GaugeVec
+
+	// edac
+	edacMCCorrectable         *prometheus.GaugeVec
+	edacMCUncorrectable       *prometheus.GaugeVec
+	edacMCCorrectableNoInfo   *prometheus.GaugeVec
+	edacMCUncorrectableNoInfo *prometheus.GaugeVec
+	edacCSRowCorrectable      *prometheus.GaugeVec
+	edacCSRowUncorrectable    *prometheus.GaugeVec
+	edacDIMMCorrectable       *prometheus.GaugeVec
+	edacDIMMUncorrectable     *prometheus.GaugeVec
+
+	// nfs
+	nfsClientPackets                 *prometheus.GaugeVec
+	nfsClientTCPConnections          *prometheus.GaugeVec
+	nfsClientRPCCalls                *prometheus.GaugeVec
+	nfsClientRPCRetransmits          *prometheus.GaugeVec
+	nfsClientRPCAuthRefreshes        *prometheus.GaugeVec
+	nfsClientRequests                *prometheus.GaugeVec
+	nfsMountAge                      *prometheus.GaugeVec
+	nfsMountReadBytes                *prometheus.GaugeVec
+	nfsMountWriteBytes               *prometheus.GaugeVec
+	nfsMountTransportSends           *prometheus.GaugeVec
+	nfsMountTransportReceives        *prometheus.GaugeVec
+	nfsMountTransportBadXIDs         *prometheus.GaugeVec
+	nfsMountOperations               *prometheus.GaugeVec
+	nfsMountOperationRetransmissions *prometheus.GaugeVec
+	nfsMountOperationTimeouts        *prometheus.GaugeVec
+	nfsMountOperationSentBytes       *prometheus.GaugeVec
+	nfsMountOperationReceivedBytes   *prometheus.GaugeVec
+	nfsMountOperationQueueTime       *prometheus.GaugeVec
+	nfsMountOperationRTT             *prometheus.GaugeVec
+	nfsMountOperationExecuteTime     *prometheus.GaugeVec
+	nfsMountOperationErrors          *prometheus.GaugeVec
+
 	// smart: generic
 	smartPowerCycles  *prometheus.GaugeVec
 	smartPowerOnHours *prometheus.GaugeVec
@@ -197,6 +410,17 @@ func NewMetrics() {
 		},
 	)
 
+	vVendorCheck = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_vendor_check",
+			Help: "1 if the bios vendor matched vendor_check.vendors, 0 if on_mismatch was applied (only set when check_vendor is true)",
+		},
+		[]string{
+			"vendor",
+			"on_mismatch",
+		},
+	)
+
 	// load avg metrics
 	loadavgLoad1 = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -327,6 +551,83 @@ func NewMetrics() {
 		[]string{},
 	)
 
+	cpuNicePct = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_nice_pct",
+			Help: "nice utilization cpu percent",
+		},
+		[]string{},
+	)
+
+	cpuCoreUtilPct = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_core_util_pct",
+			Help: "utilization cpu percent per core",
+		},
+		[]string{
+			"cpu",
+		},
+	)
+
+	cpuCoreModePct = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_core_mode_pct",
+			Help: "cpu percent per core and mode (user, nice, system, idle, iowait, irq, softirq, steal, guest, guest_nice)",
+		},
+		[]string{
+			"cpu",
+			"mode",
+		},
+	)
+
+	cpuContextSwitches = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_context_switches",
+			Help: "total context switches since boot",
+		},
+		[]string{},
+	)
+
+	cpuInterrupts = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_interrupts",
+			Help: "total interrupts serviced since boot",
+		},
+		[]string{},
+	)
+
+	cpuForks = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_forks",
+			Help: "total forks (processes and threads created) since boot",
+		},
+		[]string{},
+	)
+
+	cpuProcsRunning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_procs_running",
+			Help: "processes in runnable state",
+		},
+		[]string{},
+	)
+
+	cpuProcsBlocked = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_procs_blocked",
+			Help: "processes blocked waiting for I/O",
+		},
+		[]string{},
+	)
+
+	cpuBootTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cpu_boot_time",
+			Help: "boot time in seconds since the epoch",
+		},
+		[]string{},
+	)
+
 	// memory metrics
 	memoryTotal = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -376,6 +677,14 @@ func NewMetrics() {
 		[]string{},
 	)
 
+	memoryAvailablePct = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_memory_available_pct",
+			Help: "available memory (MemAvailable) percent of total memory",
+		},
+		[]string{},
+	)
+
 	// nic metrics
 	nicBytes = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -598,138 +907,183 @@ func NewMetrics() {
 	)
 
 	// disk stats
+	// disk_stats.by_id keys the series by the /dev/disk/by-id name so a rename (sda becoming sdb) doesn't start new
+	// series, off by default to keep the device label existing dashboards select on
+	diskLabels := []string{"device"}
+	if config.GetDiskStatsByID() {
+		diskLabels = []string{"id"}
+	}
+
 	diskStatsReads = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_read",
 			Help: "disk stats: read",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsReadsMerged = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_reads_merged",
 			Help: "disk stats: merged reads",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsSectorsRead = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_sectors_read",
 			Help: "disk stats: sectors",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsMillisecondsReading = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_ms_reading",
 			Help: "disk stats: milliseconds reading",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsWritesCompleted = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_writes_completed",
 			Help: "disk stats: writes completed",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsWritesMerged = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_writes_merged",
 			Help: "disk stats: writes merged",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsSectorsWritten = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_sectors_written",
 			Help: "disk stats: sectors written",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsMillisecondsWriting = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_ms_writing",
 			Help: "disk stats: milliseconds writing",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsIOsInProgress = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_io_ip",
 			Help: "disk stats: IO in progress",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsMillisecondsInIOs = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_ms_in_io",
 			Help: "disk stats: milliseconds in IO",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsWeightedIOsInMS = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_weighted_io_in_ms",
 			Help: "disk stats: weighted IOs in milliseconds",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsDiscards = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_discards",
 			Help: "disk stats: discards",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsDiscardsMerged = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_discards_merged",
 			Help: "disk stats: merged discards",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsSectorsDiscarded = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_sectors_discarded",
 			Help: "disk stats: sectors discarded",
 		},
-		[]string{
-			"device",
-		},
+		diskLabels,
 	)
 	diskStatsMillisecondsDiscarding = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "v_disk_stats_ms_discarding",
 			Help: "disk stats: milliseconds discarding",
 		},
+		diskLabels,
+	)
+
+	// disk stats: derived
+	diskReadBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_read_bytes",
+			Help: "disk: bytes read per second",
+		},
+		diskLabels,
+	)
+	diskWriteBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_write_bytes",
+			Help: "disk: bytes written per second",
+		},
+		diskLabels,
+	)
+	diskReadIOPS = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_read_iops",
+			Help: "disk: reads completed per second",
+		},
+		diskLabels,
+	)
+	diskWriteIOPS = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_write_iops",
+			Help: "disk: writes completed per second",
+		},
+		diskLabels,
+	)
+	diskReadAwait = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_read_await_ms",
+			Help: "disk: average read latency in milliseconds (r_await)",
+		},
+		diskLabels,
+	)
+	diskWriteAwait = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_write_await_ms",
+			Help: "disk: average write latency in milliseconds (w_await)",
+		},
+		diskLabels,
+	)
+	diskQueueDepth = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_queue_depth",
+			Help: "disk: average requests in flight (aqu-sz)",
+		},
+		diskLabels,
+	)
+	diskUtil = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_util_pct",
+			Help: "disk: percentage of time the device had I/O in flight (%util)",
+		},
+		diskLabels,
+	)
+	diskInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_disk_info",
+			Help: "disk: always 1, maps the /dev/disk/by-id name (kernel name if none) to the current kernel device name",
+		},
 		[]string{
+			"id",
 			"device",
 		},
 	)
@@ -743,6 +1097,7 @@ func NewMetrics() {
 		[]string{
 			"device",
 			"mount",
+			"fstype",
 		},
 	)
 	fsInodesUsed = promauto.NewGaugeVec(
@@ -753,6 +1108,7 @@ func NewMetrics() {
 		[]string{
 			"device",
 			"mount",
+			"fstype",
 		},
 	)
 	fsInodesUtil = promauto.NewGaugeVec(
@@ -763,6 +1119,7 @@ func NewMetrics() {
 		[]string{
 			"device",
 			"mount",
+			"fstype",
 		},
 	)
 	fsBytes = promauto.NewGaugeVec(
@@ -773,6 +1130,7 @@ func NewMetrics() {
 		[]string{
 			"device",
 			"mount",
+			"fstype",
 		},
 	)
 	fsBytesUsed = promauto.NewGaugeVec(
@@ -783,6 +1141,7 @@ func NewMetrics() {
 		[]string{
 			"device",
 			"mount",
+			"fstype",
 		},
 	)
 	fsBytesUtil = promauto.NewGaugeVec(
@@ -793,6 +1152,29 @@ func NewMetrics() {
 		[]string{
 			"device",
 			"mount",
+			"fstype",
+		},
+	)
+	fsReadOnly = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_fs_readonly",
+			Help: "filesystem mounted read-only",
+		},
+		[]string{
+			"device",
+			"mount",
+			"fstype",
+		},
+	)
+	fsDeviceError = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_fs_device_error",
+			Help: "filesystem statfs failed or timed out",
+		},
+		[]string{
+			"device",
+			"mount",
+			"fstype",
 		},
 	)
 
@@ -859,1385 +1241,3963 @@ func NewMetrics() {
 		[]string{},
 	)
 
-	// smart
-	smartPowerCycles = promauto.NewGaugeVec(
+	// psi
+	psiAvg10 = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_power_cycles",
-			Help: "power cycles",
+			Name: "v_psi_avg10",
+			Help: "pressure stall information: percent of time stalled over 10 seconds",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"resource",
+			"type",
 		},
 	)
-
-	smartPowerOnHours = promauto.NewGaugeVec(
+	psiAvg60 = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_power_on_hours",
-			Help: "power on hours",
+			Name: "v_psi_avg60",
+			Help: "pressure stall information: percent of time stalled over 60 seconds",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"resource",
+			"type",
 		},
 	)
-
-	smartNvmeCriticalWarning = promauto.NewGaugeVec(
+	psiAvg300 = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_critical_warning",
-			Help: "nvme: critical warning",
+			Name: "v_psi_avg300",
+			Help: "pressure stall information: percent of time stalled over 300 seconds",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"resource",
+			"type",
 		},
 	)
-
-	smartNvmeTemperature = promauto.NewGaugeVec(
+	psiStallTotal = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_temperate",
-			Help: "nvme: temperature",
+			Name: "v_psi_stall_seconds_total",
+			Help: "pressure stall information: total time stalled in seconds (counter)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"resource",
+			"type",
 		},
 	)
-
-	smartNvmeAvailSpare = promauto.NewGaugeVec(
+	psiCgroupAvg10 = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_avail_spare",
-			Help: "nvme: avail spare",
+			Name: "v_psi_cgroup_avg10",
+			Help: "pressure stall information per cgroup: percent of time stalled over 10 seconds",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cgroup",
+			"resource",
+			"type",
 		},
 	)
-
-	smartNvmePercentUsed = promauto.NewGaugeVec(
+	psiCgroupAvg60 = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_percent_used",
-			Help: "nvme: percent used",
+			Name: "v_psi_cgroup_avg60",
+			Help: "pressure stall information per cgroup: percent of time stalled over 60 seconds",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cgroup",
+			"resource",
+			"type",
 		},
 	)
-
-	smartNvmeEnduranceCritWarning = promauto.NewGaugeVec(
+	psiCgroupAvg300 = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_crit_warning",
-			Help: "nvme: crit warning",
+			Name: "v_psi_cgroup_avg300",
+			Help: "pressure stall information per cgroup: percent of time stalled over 300 seconds",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cgroup",
+			"resource",
+			"type",
 		},
 	)
-
-	smartNvmeDataUnitsRead = promauto.NewGaugeVec(
+	psiCgroupStallTotal = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_data_units_read",
-			Help: "nvme: data units read",
+			Name: "v_psi_cgroup_stall_seconds_total",
+			Help: "pressure stall information per cgroup: total time stalled in seconds (counter)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cgroup",
+			"resource",
+			"type",
 		},
 	)
 
-	smartNvmeDataUnitsWritten = promauto.NewGaugeVec(
+	// conntrack
+	conntrackEntries = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_data_units_written",
-			Help: "nvme: data units written",
+			Name: "v_conntrack_entries",
+			Help: "conntrack: entries in the connection tracking table (nf_conntrack_count)",
 		},
-		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+		[]string{},
+	)
+	conntrackEntriesLimit = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_conntrack_entries_limit",
+			Help: "conntrack: max entries in the connection tracking table (nf_conntrack_max)",
 		},
+		[]string{},
 	)
 
-	smartNvmeHostReads = promauto.NewGaugeVec(
+	// softnet
+	softnetProcessed = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_host_reads",
-			Help: "nvme: host reads",
+			Name: "v_softnet_processed",
+			Help: "softnet: packets processed",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartNvmeHostWrites = promauto.NewGaugeVec(
+	softnetDropped = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_host_writes",
-			Help: "nvme: host writes",
+			Name: "v_softnet_dropped",
+			Help: "softnet: packets dropped because the backlog queue was full (netdev_max_backlog)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartNvmeCtrlBusyTime = promauto.NewGaugeVec(
+	softnetTimeSqueeze = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_ctrl_busy_time",
-			Help: "nvme: controller busy time",
+			Name: "v_softnet_time_squeeze",
+			Help: "softnet: times net_rx_action ran out of budget or time with work remaining (netdev_budget)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartNvmeUnsafeShutdowns = promauto.NewGaugeVec(
+	softnetReceivedRPS = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_unsafe_shutdowns",
-			Help: "nvme: unsafe shutdowns",
+			Name: "v_softnet_received_rps",
+			Help: "softnet: times the cpu was woken up to process packets via an inter-processor interrupt (rps)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartNvmeMediaErrors = promauto.NewGaugeVec(
+	softnetFlowLimitCount = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_media_errors",
-			Help: "nvme: media errors",
+			Name: "v_softnet_flow_limit_count",
+			Help: "softnet: times the flow limit was reached",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartNvmeNumErrLogEntries = promauto.NewGaugeVec(
+	softnetBacklogLen = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_err_log_entries",
-			Help: "nvme: error log entries",
+			Name: "v_softnet_backlog_len",
+			Help: "softnet: current backlog queue length (kernel >= 5.10)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
 
-	smartNvmeWarningTempTime = promauto.NewGaugeVec(
+	// tcp states
+	tcpConnections = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_warning_temp_time",
-			Help: "nvme: warning temp time",
+			Name: "v_tcp_connections",
+			Help: "tcp sockets per state",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"family",
+			"state",
 		},
 	)
-
-	smartNvmeCritCompTime = promauto.NewGaugeVec(
+	tcpConnectionsPort = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_nvme_crit_comp_time",
-			Help: "nvme: crit comp time",
+			Name: "v_tcp_connections_port",
+			Help: "tcp sockets per state for configured local ports",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"family",
+			"state",
+			"port",
 		},
 	)
 
-	smartSataPercentLifetimeRemain = promauto.NewGaugeVec(
+	// ethtool
+	nicLinkSpeed = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_percent_lifetime_remain",
-			Help: "sata: percent lifetime remain",
+			Name: "v_nic_link_speed_mbps",
+			Help: "nic link speed in Mb/s, -1 if unknown",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"nic",
 		},
 	)
-
-	smartSataTotalLBAsWritten = promauto.NewGaugeVec(
+	nicLinkFullDuplex = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_total_lbas_written",
-			Help: "sata: total LBAs written",
+			Name: "v_nic_link_full_duplex",
+			Help: "nic duplex, 1 = full, 0 = half, -1 = unknown",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"nic",
 		},
 	)
-
-	smartSataReallocateNANDBlkCnt = promauto.NewGaugeVec(
+	nicCarrier = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_reallocate_nand_block_count",
-			Help: "sata: reallocated NAND block count",
+			Name: "v_nic_carrier",
+			Help: "nic carrier (physical link), 1 = up, 0 = down, -1 = unknown (interface administratively down)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"nic",
 		},
 	)
-
-	smartSataOfflineUncorrectable = promauto.NewGaugeVec(
+	nicMTU = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_offline_uncorrectable",
-			Help: "sata: offline uncorrectable",
+			Name: "v_nic_mtu",
+			Help: "nic mtu",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"nic",
 		},
 	)
-
-	smartSataRawReadErrorRate = promauto.NewGaugeVec(
+	nicOperState = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_raw_read_error_rate",
-			Help: "sata: raw read error rate",
+			Name: "v_nic_operstate",
+			Help: "nic operational state (RFC2863), 1 for the current state",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"nic",
+			"operstate",
 		},
 	)
-
-	smartSataReportedUncorrect = promauto.NewGaugeVec(
+	ethtoolInfo = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_reported_uncorrect",
-			Help: "sata: reported uncorrect",
+			Name: "v_ethtool_info",
+			Help: "nic driver information (metadata), always 1",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"nic",
+			"driver",
+			"version",
+			"firmware_version",
+			"bus_info",
 		},
 	)
 
-	smartSataErrorCorrectionCount = promauto.NewGaugeVec(
+	// hwmon
+	hwmonTemp = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_error_correction_count",
-			Help: "sata: error correction count",
+			Name: "v_hwmon_temp_celsius",
+			Help: "hwmon: temperature in celsius",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"chip",
+			"chip_name",
+			"sensor",
+			"label",
 		},
 	)
-
-	smartSataWriteErrorRate = promauto.NewGaugeVec(
+	hwmonTempCrit = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_write_error_rate",
-			Help: "sata: write error rate",
+			Name: "v_hwmon_temp_crit_celsius",
+			Help: "hwmon: critical temperature in celsius",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"chip",
+			"chip_name",
+			"sensor",
+			"label",
 		},
 	)
-
-	smartSataEraseFailCount = promauto.NewGaugeVec(
+	hwmonFan = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_erase_fail_count",
-			Help: "sata: erase fail acount",
+			Name: "v_hwmon_fan_rpm",
+			Help: "hwmon: fan speed in rpm",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"chip",
+			"chip_name",
+			"sensor",
+			"label",
 		},
 	)
-
-	smartSataProgramFailCount = promauto.NewGaugeVec(
+	hwmonIn = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_program_fail_count",
-			Help: "sata: program fail acount",
+			Name: "v_hwmon_in_volts",
+			Help: "hwmon: voltage in volts",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"chip",
+			"chip_name",
+			"sensor",
+			"label",
 		},
 	)
-
-	smartSataReallocatedEventCount = promauto.NewGaugeVec(
+	hwmonPower = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_reallocated_event_count",
-			Help: "sata: reallocated event count",
+			Name: "v_hwmon_power_watts",
+			Help: "hwmon: power in watts",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"chip",
+			"chip_name",
+			"sensor",
+			"label",
 		},
 	)
-
-	smartSataHostProgramPageCount = promauto.NewGaugeVec(
+	hwmonAlarm = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_host_program_page_count",
-			Help: "sata: host program page count",
+			Name: "v_hwmon_alarm",
+			Help: "hwmon: sensor alarm, 1 if raised",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"chip",
+			"chip_name",
+			"sensor",
+			"label",
+			"type",
 		},
 	)
-
-	smartSataUnusedReserveNANDBlk = promauto.NewGaugeVec(
+	thermalZoneTemp = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_unused_reserve_nand_block",
-			Help: "sata: unused reserve NAND block",
+			Name: "v_thermal_zone_temp_celsius",
+			Help: "thermal zone: temperature in celsius",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"zone",
+			"type",
 		},
 	)
-
-	smartSataAveBlockEraseCount = promauto.NewGaugeVec(
+	thermalCoolingCurState = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_avg_block_erase_count",
-			Help: "sata: average block erase count",
+			Name: "v_thermal_cooling_device_cur_state",
+			Help: "thermal cooling device: current throttle state",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
+			"type",
 		},
 	)
-
-	smartSataSATAInterfacDownshift = promauto.NewGaugeVec(
+	thermalCoolingMaxState = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_interface_downshift",
-			Help: "sata: interface downshift",
+			Name: "v_thermal_cooling_device_max_state",
+			Help: "thermal cooling device: maximum throttle state",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
+			"type",
 		},
 	)
 
-	smartSataUnexpectPowerLossCt = promauto.NewGaugeVec(
+	// cpufreq
+	cpuFreqCur = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_unexpected_powerloss_count",
-			Help: "sata: unexpected powerloss count",
+			Name: "v_cpu_frequency_hz",
+			Help: "cpufreq: current frequency in hertz",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartSataTemperatureCelsius = promauto.NewGaugeVec(
+	cpuFreqMin = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_temperature_celsius",
-			Help: "sata: temperature in celsius",
+			Name: "v_cpu_frequency_min_hz",
+			Help: "cpufreq: minimum frequency in hertz",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartSataCurrentPendingECCCnt = promauto.NewGaugeVec(
+	cpuFreqMax = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_current_pending_ecc_count",
-			Help: "sata: current pending ECC count",
+			Name: "v_cpu_frequency_max_hz",
+			Help: "cpufreq: maximum frequency in hertz",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartSataUDMACRCErrorCount = promauto.NewGaugeVec(
+	cpuFreqGovernor = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_udma_crc_error_count",
-			Help: "sata: UDMA CRC error count",
+			Name: "v_cpu_scaling_governor",
+			Help: "cpufreq: scaling governor, always 1",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
+			"governor",
 		},
 	)
-
-	smartSataFTLProgramPageCount = promauto.NewGaugeVec(
+	cpuCoreThrottles = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_ftl_program_page_count",
-			Help: "sata: FTL program page count",
+			Name: "v_cpu_core_throttles",
+			Help: "thermal_throttle: times the core was throttled",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartSataSuccessRAINRecovCnt = promauto.NewGaugeVec(
+	cpuCoreThrottleTime = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_success_rain_recovery_count",
-			Help: "sata: success RAIN recovery count",
+			Name: "v_cpu_core_throttle_time_ms",
+			Help: "thermal_throttle: milliseconds the core was throttled",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"cpu",
 		},
 	)
-
-	smartSataPercentLifeRemaining = promauto.NewGaugeVec(
+	cpuPackageThrottles = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_percent_life_remaining",
-			Help: "sata: percent life remaining",
+			Name: "v_cpu_package_throttles",
+			Help: "thermal_throttle: times the package was throttled",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"package",
 		},
 	)
-
-	smartSataReallocatedSectorCt = promauto.NewGaugeVec(
+	cpuPackageThrottleTime = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_reallocated_sector_count",
-			Help: "sata: reallocated sector count",
+			Name: "v_cpu_package_throttle_time_ms",
+			Help: "thermal_throttle: milliseconds the package was throttled",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"package",
 		},
 	)
 
-	smartSataAvailableReservdSpace = promauto.NewGaugeVec(
+	// mdadm
+	mdState = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_available_reserved_space",
-			Help: "sata: available reserved space",
+			Name: "v_md_state",
+			Help: "md: array state, always 1",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
+			"level",
+			"state",
 		},
 	)
-
-	smartSataEraseFailCountTotal = promauto.NewGaugeVec(
+	mdDisks = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_erase_fail_count_total",
-			Help: "sata: erase fail count total",
+			Name: "v_md_disks",
+			Help: "md: member disks by state (active, failed, spare)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
+			"state",
 		},
 	)
-
-	smartSataPowerLossCapTest = promauto.NewGaugeVec(
+	mdDisksRequired = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_power_loss_cap_test",
-			Help: "sata: power loss cap test",
+			Name: "v_md_disks_required",
+			Help: "md: disks the array should have",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
 		},
 	)
-
-	smartSataTotalLBAsRead = promauto.NewGaugeVec(
+	mdDegraded = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_total_lbas_read",
-			Help: "sata: total LBAs read",
+			Name: "v_md_degraded",
+			Help: "md: missing disks, 0 if the array is not degraded",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
 		},
 	)
-
-	smartSataReadSoftErrorRate = promauto.NewGaugeVec(
+	mdSyncAction = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_read_soft_error_rate",
-			Help: "sata: read soft error rate",
+			Name: "v_md_sync_action",
+			Help: "md: current sync action (idle, resync, recover, check, repair, reshape), always 1",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
+			"action",
 		},
 	)
-
-	smartSataEndtoEndError = promauto.NewGaugeVec(
+	mdSyncProgress = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_end_to_end_error",
-			Help: "sata: end-to-end error",
+			Name: "v_md_sync_progress_pct",
+			Help: "md: sync progress percentage, 100 when idle",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
 		},
 	)
-
-	smartSataUncorrectableErrorCnt = promauto.NewGaugeVec(
+	mdSyncFinish = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_uncorrectable_error_count",
-			Help: "sata: uncorrectable error count",
+			Name: "v_md_sync_finish_seconds",
+			Help: "md: estimated seconds until the sync completes",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
 		},
 	)
-
-	smartSataCRCErrorCount = promauto.NewGaugeVec(
+	mdSyncSpeed = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_crc_error_count",
-			Help: "sata: CRC error count",
+			Name: "v_md_sync_speed_bytes",
+			Help: "md: sync speed in bytes per second",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
 			"device",
 		},
 	)
 
-	smartSataThermalThrottleStatus = promauto.NewGaugeVec(
+	// processes
+	processGroupCount = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_thermal_throttle_status",
-			Help: "sata: thermal throttle status",
+			Name: "v_process_group_count",
+			Help: "processes: processes in the group",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
 		},
 	)
-
-	smartSataUnsafeShutdownCount = promauto.NewGaugeVec(
+	processGroupRunning = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_unsafe_shutdown_count",
-			Help: "sata: unsafe shutdown count",
+			Name: "v_process_group_running",
+			Help: "processes: 1 if at least one process in the group is running",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
 		},
 	)
-
-	smartSataProgramFailCountChip = promauto.NewGaugeVec(
+	processGroupThreads = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_program_fail_count_chip",
-			Help: "sata: program fail count chip",
+			Name: "v_process_group_threads",
+			Help: "processes: threads of every process in the group",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
 		},
 	)
-
-	smartSataUsedRsvdBlkCntTot = promauto.NewGaugeVec(
+	processGroupRSS = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_used_reserved_block_count_total",
-			Help: "sata: used reserved block count total",
+			Name: "v_process_group_rss_bytes",
+			Help: "processes: resident memory of every process in the group in bytes",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
 		},
 	)
-
-	smartSataUnusedRsvdBlkCntTot = promauto.NewGaugeVec(
+	processGroupOpenFDs = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_unused_reserved_block_count_total",
-			Help: "sata: unused reserved block count total",
+			Name: "v_process_group_open_fds",
+			Help: "processes: open file descriptors of every process in the group",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
 		},
 	)
-
-	smartSataProgramFailCntTotal = promauto.NewGaugeVec(
+	processGroupCPUUser = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_program_fail_count_total",
-			Help: "sata: program fail count total",
+			Name: "v_process_group_cpu_user_seconds",
+			Help: "processes: user cpu time of the group in seconds, processes that exited since v-agent started included",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
 		},
 	)
-
-	smartSataCurrentPendingSector = promauto.NewGaugeVec(
+	processGroupCPUSystem = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_current_pending_sector",
-			Help: "sata: current pending sector",
+			Name: "v_process_group_cpu_system_seconds",
+			Help: "processes: system cpu time of the group in seconds, processes that exited since v-agent started included",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
 		},
 	)
-
-	smartSataEndofLife = promauto.NewGaugeVec(
+	processGroupStartTime = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_end_of_life",
-			Help: "sata: end of life",
+			Name: "v_process_group_start_time",
+			Help: "processes: start time of the oldest process in the group as a unix timestamp",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
-			"device",
+			"group",
+		},
+	)
+	processGroupRestarts = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_process_group_restarts",
+			Help: "processes: times the oldest process in the group changed since v-agent started",
+		},
+		[]string{
+			"group",
 		},
 	)
 
-	smartSataHardwareECCRecovered = promauto.NewGaugeVec(
+	// cgroups
+	cgroupCPUUsage = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_hardware_ecc_recovered",
-			Help: "sata: hardware ECC recovered",
+			Name: "v_cgroup_cpu_usage_seconds",
+			Help: "cgroup: cpu time in seconds (cpu.stat usage_usec)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupCPUUser = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_cpu_user_seconds",
+			Help: "cgroup: user cpu time in seconds (cpu.stat user_usec)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupCPUSystem = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_cpu_system_seconds",
+			Help: "cgroup: system cpu time in seconds (cpu.stat system_usec)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupCPUPeriods = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_cpu_periods",
+			Help: "cgroup: enforcement periods elapsed (cpu.stat nr_periods)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupCPUThrottledPeriods = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_cpu_throttled_periods",
+			Help: "cgroup: periods throttled (cpu.stat nr_throttled)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupCPUThrottled = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_cpu_throttled_seconds",
+			Help: "cgroup: time throttled in seconds (cpu.stat throttled_usec)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupMemoryCurrent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_memory_current_bytes",
+			Help: "cgroup: memory usage in bytes (memory.current)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupMemoryMax = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_memory_max_bytes",
+			Help: "cgroup: memory limit in bytes (memory.max), not set if unlimited",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupMemoryEvents = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_memory_events",
+			Help: "cgroup: memory.events (low, high, max, oom, oom_kill)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+			"event",
+		},
+	)
+	cgroupIORead = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_io_read_bytes",
+			Help: "cgroup: bytes read (io.stat rbytes)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
 			"device",
 		},
 	)
-
-	smartSataSoftReadErrorRate = promauto.NewGaugeVec(
+	cgroupIOWrite = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_soft_read_error_rate",
-			Help: "sata: soft read error rate",
+			Name: "v_cgroup_io_write_bytes",
+			Help: "cgroup: bytes written (io.stat wbytes)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
+			"cgroup",
+			"pod_uid",
+			"container_id",
 			"device",
 		},
 	)
-
-	smartSataMediaWearoutIndicator = promauto.NewGaugeVec(
+	cgroupIOReads = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_media_wearout_indicator",
-			Help: "sata: media wearout indicator",
+			Name: "v_cgroup_io_reads",
+			Help: "cgroup: read operations (io.stat rios)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
+			"cgroup",
+			"pod_uid",
+			"container_id",
 			"device",
 		},
 	)
-
-	smartSataDataAddressMarkErrs = promauto.NewGaugeVec(
+	cgroupIOWrites = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
-			Name: "v_smart_sata_data_address_mark_errors",
-			Help: "sata: data address mark errors",
+			Name: "v_cgroup_io_writes",
+			Help: "cgroup: write operations (io.stat wios)",
 		},
 		[]string{
-			"model",
-			"serial",
-			"firmware",
+			"cgroup",
+			"pod_uid",
+			"container_id",
 			"device",
 		},
 	)
-}
+	cgroupPidsCurrent = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_pids_current",
+			Help: "cgroup: processes and threads (pids.current)",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
+	cgroupPidsMax = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_cgroup_pids_max",
+			Help: "cgroup: process and thread limit (pids.max), not set if unlimited",
+		},
+		[]string{
+			"cgroup",
+			"pod_uid",
+			"container_id",
+		},
+	)
 
-// Gather gathers updates metrics
-func Gather() error {
-	log := zap.L().Sugar()
+	// systemd
+	systemdUnitState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_systemd_unit_state",
+			Help: "systemd: 1 if the unit is in the active state (active, failed, etc), 0 otherwise",
+		},
+		[]string{
+			"unit",
+			"state",
+		},
+	)
+	systemdUnitInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_systemd_unit_info",
+			Help: "systemd: unit load and sub state, always 1",
+		},
+		[]string{
+			"unit",
+			"load_state",
+			"sub_state",
+		},
+	)
+	systemdUnitActiveEnterTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_systemd_unit_active_enter_time",
+			Help: "systemd: unix timestamp the unit last entered the active state",
+		},
+		[]string{
+			"unit",
+		},
+	)
+	systemdUnitRestarts = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_systemd_unit_restarts",
+			Help: "systemd: times the service was restarted by systemd (NRestarts)",
+		},
+		[]string{
+			"unit",
+		},
+	)
+	systemdTimerLastTrigger = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_systemd_timer_last_trigger_time",
+			Help: "systemd: unix timestamp the timer last triggered",
+		},
+		[]string{
+			"unit",
+		},
+	)
 
-	if err := gatherMetadataMetrics(); err != nil {
-		if !errors.Is(err, config.ErrLabelNotExist) {
-			return err
-		} else {
-			log.Warn(err)
-		}
-	}
+	// timex
+	timexOffset = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_offset_seconds",
+			Help: "timex: time offset between the local clock and the reference clock in seconds",
+		},
+		[]string{},
+	)
+	timexFrequency = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_frequency_adjustment_ratio",
+			Help: "timex: local clock frequency adjustment ratio",
+		},
+		[]string{},
+	)
+	timexMaxError = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_maxerror_seconds",
+			Help: "timex: maximum error in seconds",
+		},
+		[]string{},
+	)
+	timexEstError = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_estimated_error_seconds",
+			Help: "timex: estimated error in seconds",
+		},
+		[]string{},
+	)
+	timexStatus = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_status",
+			Help: "timex: clock status bits (STA_*)",
+		},
+		[]string{},
+	)
+	timexState = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_state",
+			Help: "timex: clock state (0 TIME_OK, 1 TIME_INS, 2 TIME_DEL, 3 TIME_OOP, 4 TIME_WAIT, 5 TIME_ERROR)",
+		},
+		[]string{},
+	)
+	timexSyncStatus = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_sync_status",
+			Help: "timex: 1 if the clock is synchronized to a reliable server (no STA_UNSYNC and no TIME_ERROR)",
+		},
+		[]string{},
+	)
+	timexUnsynchronized = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_unsynchronized_seconds",
+			Help: "timex: seconds since v-agent first saw the clock unsynchronized, 0 if synchronized",
+		},
+		[]string{},
+	)
+	timexTAIOffset = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_tai_offset_seconds",
+			Help: "timex: international atomic time (TAI) offset in seconds",
+		},
+		[]string{},
+	)
+	timexTimeConstant = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_loop_time_constant",
+			Help: "timex: phase-locked loop time constant",
+		},
+		[]string{},
+	)
+	timexTick = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_timex_tick_seconds",
+			Help: "timex: seconds between clock ticks",
+		},
+		[]string{},
+	)
+	timexTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_time_seconds",
+			Help: "timex: system time in unix seconds, compare with timestamp() to detect drift",
+		},
+		[]string{},
+	)
 
-	if config.LoadAvgMetricCollectionEnabled() {
-		log.Info("Gathering load_avg metrics")
-		if err := gatherLoadavgMetrics(); err != nil {
-			return err
-		}
-	} else {
+	// node info
+	nodeUnameInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_node_uname_info",
+			Help: "node: kernel and host identity from uname(2), always 1",
+		},
+		[]string{
+			"sysname",
+			"release",
+			"version",
+			"machine",
+			"nodename",
+			"domainname",
+		},
+	)
+	nodeOSReleaseInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_os_release_info",
+			Help: "node: operating system identification from os-release, always 1",
+		},
+		[]string{
+			"id",
+			"id_like",
+			"name",
+			"pretty_name",
+			"version",
+			"version_id",
+			"version_codename",
+		},
+	)
+	nodeDMIInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_dmi_info",
+			Help: "node: hardware identification from DMI (/sys/devices/virtual/dmi/id), always 1",
+		},
+		[]string{
+			"bios_vendor",
+			"bios_version",
+			"bios_date",
+			"sys_vendor",
+			"product_name",
+			"product_version",
+			"board_vendor",
+			"board_name",
+			"chassis_vendor",
+			"chassis_type",
+		},
+	)
+	nodeUptime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_uptime_seconds",
+			Help: "node: seconds since boot",
+		},
+		[]string{},
+	)
+
+	// kernel limits
+	kernelFileAllocated = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_file_allocated",
+			Help: "kernel limits: allocated file handles (fs.file-nr)",
+		},
+		[]string{},
+	)
+	kernelFileMax = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_file_max",
+			Help: "kernel limits: maximum file handles (fs.file-max)",
+		},
+		[]string{},
+	)
+	kernelInodeTotal = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_inode_allocated",
+			Help: "kernel limits: allocated inodes (fs.inode-nr)",
+		},
+		[]string{},
+	)
+	kernelInodeFree = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_inode_free",
+			Help: "kernel limits: free allocated inodes (fs.inode-nr)",
+		},
+		[]string{},
+	)
+	kernelEntropyAvail = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_entropy_available_bits",
+			Help: "kernel limits: bits of entropy available (kernel.random.entropy_avail)",
+		},
+		[]string{},
+	)
+	kernelEntropyPoolSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_entropy_pool_size_bits",
+			Help: "kernel limits: size of the entropy pool in bits (kernel.random.poolsize)",
+		},
+		[]string{},
+	)
+	kernelPIDMax = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_pid_max",
+			Help: "kernel limits: maximum pid (kernel.pid_max), also limits threads",
+		},
+		[]string{},
+	)
+	kernelThreadsMax = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_threads_max",
+			Help: "kernel limits: maximum threads (kernel.threads-max)",
+		},
+		[]string{},
+	)
+	kernelThreads = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_threads",
+			Help: "kernel limits: current threads (scheduling entities)",
+		},
+		[]string{},
+	)
+	kernelInotifyMaxWatches = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_inotify_max_user_watches",
+			Help: "kernel limits: maximum inotify watches per user (fs.inotify.max_user_watches)",
+		},
+		[]string{},
+	)
+	kernelInotifyMaxInstances = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_inotify_max_user_instances",
+			Help: "kernel limits: maximum inotify instances per user (fs.inotify.max_user_instances)",
+		},
+		[]string{},
+	)
+	kernelInotifyWatches = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_inotify_watches",
+			Help: "kernel limits: inotify watches of a user",
+		},
+		[]string{
+			"uid",
+		},
+	)
+	kernelInotifyInstances = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kernel_inotify_instances",
+			Help: "kernel limits: inotify instances of a user",
+		},
+		[]string{
+			"uid",
+		},
+	)
+
+	// numa
+	numaHugePagesTotal = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_numa_hugepages",
+			Help: "numa: hugepages in the pool of a node",
+		},
+		[]string{
+			"node",
+			"page_size",
+		},
+	)
+	numaHugePagesFree = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_numa_hugepages_free",
+			Help: "numa: free hugepages in the pool of a node",
+		},
+		[]string{
+			"node",
+			"page_size",
+		},
+	)
+	numaHugePagesSurplus = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_numa_hugepages_surplus",
+			Help: "numa: surplus (overcommitted) hugepages in the pool of a node",
+		},
+		[]string{
+			"node",
+			"page_size",
+		},
+	)
+
+	// kmsg
+	kmsgMatches = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kmsg_matches",
+			Help: "kmsg: kernel log messages matching a kmsg.patterns entry since v-agent started, match is the first capture group (process, device, interface)",
+		},
+		[]string{
+			"pattern",
+			"match",
+		},
+	)
+	kmsgRecords = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kmsg_records",
+			Help: "kmsg: kernel log records read since v-agent started",
+		},
+		[]string{},
+	)
+	kmsgDropped = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_kmsg_dropped_records",
+			Help: "kmsg: kernel log records overwritten in the ring buffer before v-agent read them",
+		},
+		[]string{},
+	)
+
+	// edac
+	edacMCCorrectable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_correctable_errors",
+			Help: "edac: correctable memory errors of a memory controller",
+		},
+		[]string{
+			"controller",
+			"mc_name",
+		},
+	)
+	edacMCUncorrectable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_uncorrectable_errors",
+			Help: "edac: uncorrectable memory errors of a memory controller",
+		},
+		[]string{
+			"controller",
+			"mc_name",
+		},
+	)
+	edacMCCorrectableNoInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_correctable_noinfo_errors",
+			Help: "edac: correctable memory errors that could not be attributed to a csrow",
+		},
+		[]string{
+			"controller",
+			"mc_name",
+		},
+	)
+	edacMCUncorrectableNoInfo = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_uncorrectable_noinfo_errors",
+			Help: "edac: uncorrectable memory errors that could not be attributed to a csrow",
+		},
+		[]string{
+			"controller",
+			"mc_name",
+		},
+	)
+	edacCSRowCorrectable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_csrow_correctable_errors",
+			Help: "edac: correctable memory errors of a chip select row",
+		},
+		[]string{
+			"controller",
+			"csrow",
+		},
+	)
+	edacCSRowUncorrectable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_csrow_uncorrectable_errors",
+			Help: "edac: uncorrectable memory errors of a chip select row",
+		},
+		[]string{
+			"controller",
+			"csrow",
+		},
+	)
+	edacDIMMCorrectable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_dimm_correctable_errors",
+			Help: "edac: correctable memory errors of a dimm",
+		},
+		[]string{
+			"controller",
+			"dimm",
+			"label",
+		},
+	)
+	edacDIMMUncorrectable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_edac_dimm_uncorrectable_errors",
+			Help: "edac: uncorrectable memory errors of a dimm",
+		},
+		[]string{
+			"controller",
+			"dimm",
+			"label",
+		},
+	)
+
+	// nfs
+	nfsClientPackets = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_client_packets",
+			Help: "nfs: network packets of the nfs client, /proc/net/rpc/nfs",
+		},
+		[]string{
+			"protocol",
+		},
+	)
+	nfsClientTCPConnections = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_client_tcp_connections",
+			Help: "nfs: tcp connections made by the nfs client",
+		},
+		[]string{},
+	)
+	nfsClientRPCCalls = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_client_rpc_calls",
+			Help: "nfs: rpc calls made by the nfs client",
+		},
+		[]string{},
+	)
+	nfsClientRPCRetransmits = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_client_rpc_retransmissions",
+			Help: "nfs: rpc calls retransmitted by the nfs client",
+		},
+		[]string{},
+	)
+	nfsClientRPCAuthRefreshes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_client_rpc_auth_refreshes",
+			Help: "nfs: rpc credential refreshes of the nfs client",
+		},
+		[]string{},
+	)
+	nfsClientRequests = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_client_requests",
+			Help: "nfs: requests per nfs version and op",
+		},
+		[]string{
+			"version",
+			"op",
+		},
+	)
+	nfsMountAge = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_age_seconds",
+			Help: "nfs: seconds since the share was mounted, /proc/self/mountstats",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"fstype",
+		},
+	)
+	nfsMountReadBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_read_bytes",
+			Help: "nfs: bytes read from a mount, type normal (page cache), direct (O_DIRECT) or server (from the server)",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"type",
+		},
+	)
+	nfsMountWriteBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_write_bytes",
+			Help: "nfs: bytes written to a mount, type normal (page cache), direct (O_DIRECT) or server (to the server)",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"type",
+		},
+	)
+	nfsMountTransportSends = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_transport_sends",
+			Help: "nfs: rpc requests sent on the transport of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"protocol",
+		},
+	)
+	nfsMountTransportReceives = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_transport_receives",
+			Help: "nfs: rpc replies received on the transport of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"protocol",
+		},
+	)
+	nfsMountTransportBadXIDs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_transport_bad_xids",
+			Help: "nfs: rpc replies that did not match a request on the transport of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"protocol",
+		},
+	)
+	nfsMountOperations = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operations",
+			Help: "nfs: operations per op of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationRetransmissions = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_retransmissions",
+			Help: "nfs: retransmissions per op of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationTimeouts = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_major_timeouts",
+			Help: "nfs: major timeouts per op of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationSentBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_sent_bytes",
+			Help: "nfs: bytes sent per op of a mount, rpc headers included",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationReceivedBytes = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_received_bytes",
+			Help: "nfs: bytes received per op of a mount, rpc headers included",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationQueueTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_queue_seconds",
+			Help: "nfs: cumulative seconds requests waited to be sent per op of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationRTT = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_rtt_seconds",
+			Help: "nfs: cumulative seconds from send to reply per op of a mount, rtt_seconds / operations is the average rtt",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationExecuteTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_execute_seconds",
+			Help: "nfs: cumulative seconds from queue to completion per op of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+	nfsMountOperationErrors = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_nfs_mount_operation_errors",
+			Help: "nfs: operations that completed with an error per op of a mount",
+		},
+		[]string{
+			"export",
+			"mountpoint",
+			"op",
+		},
+	)
+
+	// smart
+	smartPowerCycles = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_power_cycles",
+			Help: "power cycles",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartPowerOnHours = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_power_on_hours",
+			Help: "power on hours",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeCriticalWarning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_critical_warning",
+			Help: "nvme: critical warning",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeTemperature = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_temperate",
+			Help: "nvme: temperature",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeAvailSpare = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_avail_spare",
+			Help: "nvme: avail spare",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmePercentUsed = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_percent_used",
+			Help: "nvme: percent used",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeEnduranceCritWarning = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_crit_warning",
+			Help: "nvme: crit warning",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeDataUnitsRead = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_data_units_read",
+			Help: "nvme: data units read",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeDataUnitsWritten = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_data_units_written",
+			Help: "nvme: data units written",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeHostReads = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_host_reads",
+			Help: "nvme: host reads",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeHostWrites = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_host_writes",
+			Help: "nvme: host writes",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeCtrlBusyTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_ctrl_busy_time",
+			Help: "nvme: controller busy time",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeUnsafeShutdowns = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_unsafe_shutdowns",
+			Help: "nvme: unsafe shutdowns",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeMediaErrors = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_media_errors",
+			Help: "nvme: media errors",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeNumErrLogEntries = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_err_log_entries",
+			Help: "nvme: error log entries",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeWarningTempTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_warning_temp_time",
+			Help: "nvme: warning temp time",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartNvmeCritCompTime = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_nvme_crit_comp_time",
+			Help: "nvme: crit comp time",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataPercentLifetimeRemain = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_percent_lifetime_remain",
+			Help: "sata: percent lifetime remain",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataTotalLBAsWritten = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_total_lbas_written",
+			Help: "sata: total LBAs written",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataReallocateNANDBlkCnt = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_reallocate_nand_block_count",
+			Help: "sata: reallocated NAND block count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataOfflineUncorrectable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_offline_uncorrectable",
+			Help: "sata: offline uncorrectable",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataRawReadErrorRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_raw_read_error_rate",
+			Help: "sata: raw read error rate",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataReportedUncorrect = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_reported_uncorrect",
+			Help: "sata: reported uncorrect",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataErrorCorrectionCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_error_correction_count",
+			Help: "sata: error correction count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataWriteErrorRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_write_error_rate",
+			Help: "sata: write error rate",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataEraseFailCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_erase_fail_count",
+			Help: "sata: erase fail acount",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataProgramFailCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_program_fail_count",
+			Help: "sata: program fail acount",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataReallocatedEventCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_reallocated_event_count",
+			Help: "sata: reallocated event count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataHostProgramPageCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_host_program_page_count",
+			Help: "sata: host program page count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataUnusedReserveNANDBlk = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_unused_reserve_nand_block",
+			Help: "sata: unused reserve NAND block",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataAveBlockEraseCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_avg_block_erase_count",
+			Help: "sata: average block erase count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataSATAInterfacDownshift = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_interface_downshift",
+			Help: "sata: interface downshift",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataUnexpectPowerLossCt = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_unexpected_powerloss_count",
+			Help: "sata: unexpected powerloss count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataTemperatureCelsius = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_temperature_celsius",
+			Help: "sata: temperature in celsius",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataCurrentPendingECCCnt = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_current_pending_ecc_count",
+			Help: "sata: current pending ECC count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataUDMACRCErrorCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_udma_crc_error_count",
+			Help: "sata: UDMA CRC error count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataFTLProgramPageCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_ftl_program_page_count",
+			Help: "sata: FTL program page count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataSuccessRAINRecovCnt = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_success_rain_recovery_count",
+			Help: "sata: success RAIN recovery count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataPercentLifeRemaining = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_percent_life_remaining",
+			Help: "sata: percent life remaining",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataReallocatedSectorCt = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_reallocated_sector_count",
+			Help: "sata: reallocated sector count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataAvailableReservdSpace = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_available_reserved_space",
+			Help: "sata: available reserved space",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataEraseFailCountTotal = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_erase_fail_count_total",
+			Help: "sata: erase fail count total",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataPowerLossCapTest = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_power_loss_cap_test",
+			Help: "sata: power loss cap test",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataTotalLBAsRead = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_total_lbas_read",
+			Help: "sata: total LBAs read",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataReadSoftErrorRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_read_soft_error_rate",
+			Help: "sata: read soft error rate",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataEndtoEndError = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_end_to_end_error",
+			Help: "sata: end-to-end error",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataUncorrectableErrorCnt = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_uncorrectable_error_count",
+			Help: "sata: uncorrectable error count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataCRCErrorCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_crc_error_count",
+			Help: "sata: CRC error count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataThermalThrottleStatus = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_thermal_throttle_status",
+			Help: "sata: thermal throttle status",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataUnsafeShutdownCount = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_unsafe_shutdown_count",
+			Help: "sata: unsafe shutdown count",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataProgramFailCountChip = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_program_fail_count_chip",
+			Help: "sata: program fail count chip",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataUsedRsvdBlkCntTot = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_used_reserved_block_count_total",
+			Help: "sata: used reserved block count total",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataUnusedRsvdBlkCntTot = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_unused_reserved_block_count_total",
+			Help: "sata: unused reserved block count total",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataProgramFailCntTotal = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_program_fail_count_total",
+			Help: "sata: program fail count total",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataCurrentPendingSector = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_current_pending_sector",
+			Help: "sata: current pending sector",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataEndofLife = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_end_of_life",
+			Help: "sata: end of life",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataHardwareECCRecovered = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_hardware_ecc_recovered",
+			Help: "sata: hardware ECC recovered",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataSoftReadErrorRate = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_soft_read_error_rate",
+			Help: "sata: soft read error rate",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataMediaWearoutIndicator = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_media_wearout_indicator",
+			Help: "sata: media wearout indicator",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+
+	smartSataDataAddressMarkErrs = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "v_smart_sata_data_address_mark_errors",
+			Help: "sata: data address mark errors",
+		},
+		[]string{
+			"model",
+			"serial",
+			"firmware",
+			"device",
+		},
+	)
+}
+
+// Gather gathers updates metrics
+func Gather() error {
+	log := zap.L().Sugar()
+
+	if err := GatherMetadata(); err != nil {
+		return err
+	}
+
+	if config.LoadAvgMetricCollectionEnabled() {
+		log.Info("Gathering load_avg metrics")
+		if err := gatherLoadavgMetrics(); err != nil {
+			return err
+		}
+	} else {
 		log.Info("Not gathering load_avg metrics")
 	}
 
-	if config.CPUMetricCollectionEnabled() {
-		log.Info("Gathering cpu metrics")
-		if err := gatherCPUMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering cpu metrics")
+	if config.CPUMetricCollectionEnabled() {
+		log.Info("Gathering cpu metrics")
+		if err := gatherCPUMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering cpu metrics")
+	}
+
+	if config.MemoryMetricCollectionEnabled() {
+		log.Info("Gathering memory metrics")
+		if err := gatherMemoryMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering memory metrics")
+	}
+
+	if config.NICMetricCollectionEnabled() {
+		log.Info("Gathering nic metrics")
+		if err := gatherNICMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering nic metrics")
+	}
+
+	if config.DiskStatsMetricCollectionEnabled() {
+		log.Info("Gathering disk stats metrics")
+		if err := gatherDiskMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering disk stats metrics")
+	}
+
+	if config.FileSystemMetricCollectionEnabled() {
+		log.Info("Gathering file system metrics")
+		if err := gatherFilesystemMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering file system metrics")
+	}
+
+	if config.KubernetesMetricCollectionEnabled() {
+		log.Info("Gathering kubernetes metrics")
+		if err := gatherKubernetesMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering kubernetes metrics")
+	}
+
+	if config.KonnectivityMetricCollectionEnabled() {
+		log.Info("Gathering konnectivity metrics")
+		if err := gatherKonnectivityMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering konnectivity metrics")
+	}
+
+	if config.EtcdMetricCollectionEnabled() {
+		log.Info("Gathering etcd metrics")
+		if err := gatherEtcdMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering etcd metrics")
+	}
+
+	if config.NginxVTSMetricsCollectionEnabled() {
+		log.Info("Gathering nginx-vts metrics")
+		if err := gatherNginxVTSMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering nginx-vts metrics")
+	}
+
+	if config.VCDNAgentMetricsCollectionEnabled() {
+		log.Info("Gathering v-cdn-agent metrics")
+		if err := gatherVCDNAgentMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering v-cdn-agent metrics")
+	}
+
+	if config.HAProxyMetricCollectionEnabled() {
+		log.Info("Gathering haproxy metrics")
+		if err := gatherHAProxyMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering haproxy metrics")
+	}
+
+	if config.CephMetricCollectionEnabled() {
+		log.Info("Gathering ceph metrics")
+		if err := gatherCephMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering ceph metrics")
+	}
+
+	if config.VDNSMetricsCollectionEnabled() {
+		log.Info("Gathering v-dns metrics")
+		if err := gatherVDNSMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering v-dns metrics")
+	}
+
+	if config.KubernetesPodsCollectionEnabled() {
+		log.Info("Gathering kubernetes_pods metrics")
+		if err := gatherScrapeablePodsMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering kubernetes_pods metrics")
+	}
+
+	if config.SMARTCollectionEnabled() {
+		log.Info("Gathering SMART metrics")
+		if err := gatherSMARTmetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering SMART metrics")
+	}
+
+	if config.DCGMCollectionEnabled() {
+		log.Info("Gathering DCGM metrics")
+		if err := gatherDCGMmetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering DCGM metrics")
+	}
+
+	if config.PSIMetricCollectionEnabled() {
+		log.Info("Gathering psi metrics")
+		if err := gatherPSIMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering psi metrics")
+	}
+
+	if config.VMStatMetricCollectionEnabled() {
+		log.Info("Gathering vmstat metrics")
+		if err := gatherVMStatMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering vmstat metrics")
+	}
+
+	if config.NetStatMetricCollectionEnabled() {
+		log.Info("Gathering netstat metrics")
+		if err := gatherNetStatMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering netstat metrics")
+	}
+
+	if config.ConntrackMetricCollectionEnabled() {
+		log.Info("Gathering conntrack metrics")
+		if err := gatherConntrackMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering conntrack metrics")
+	}
+
+	if config.SoftnetMetricCollectionEnabled() {
+		log.Info("Gathering softnet metrics")
+		if err := gatherSoftnetMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering softnet metrics")
+	}
+
+	if config.TCPStatesMetricCollectionEnabled() {
+		log.Info("Gathering tcp states metrics")
+		if err := gatherTCPStatesMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering tcp states metrics")
+	}
+
+	if config.EthtoolMetricCollectionEnabled() {
+		log.Info("Gathering ethtool metrics")
+		if err := gatherEthtoolMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering ethtool metrics")
+	}
+
+	if config.HwmonMetricCollectionEnabled() {
+		log.Info("Gathering hwmon metrics")
+		if err := gatherHwmonMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering hwmon metrics")
+	}
+
+	if config.CPUFreqMetricCollectionEnabled() {
+		log.Info("Gathering cpufreq metrics")
+		if err := gatherCPUFreqMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering cpufreq metrics")
+	}
+
+	if config.MDAdmMetricCollectionEnabled() {
+		log.Info("Gathering mdadm metrics")
+		if err := gatherMDAdmMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering mdadm metrics")
+	}
+
+	if config.ProcessesMetricCollectionEnabled() {
+		log.Info("Gathering process metrics")
+		if err := gatherProcessesMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering process metrics")
+	}
+
+	if config.CgroupsMetricCollectionEnabled() {
+		log.Info("Gathering cgroup metrics")
+		if err := gatherCgroupsMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering cgroup metrics")
+	}
+
+	if config.SystemdMetricCollectionEnabled() {
+		log.Info("Gathering systemd metrics")
+		if err := gatherSystemdMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering systemd metrics")
+	}
+
+	if config.TimexMetricCollectionEnabled() {
+		log.Info("Gathering timex metrics")
+		if err := gatherTimexMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering timex metrics")
+	}
+
+	if config.NodeInfoMetricCollectionEnabled() {
+		log.Info("Gathering node_info metrics")
+		if err := gatherNodeInfoMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering node_info metrics")
+	}
+
+	if config.KernelLimitsMetricCollectionEnabled() {
+		log.Info("Gathering kernel_limits metrics")
+		if err := gatherKernelLimitsMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering kernel_limits metrics")
+	}
+
+	if config.NUMAMetricCollectionEnabled() {
+		log.Info("Gathering numa metrics")
+		if err := gatherNUMAMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering numa metrics")
+	}
+
+	if config.KmsgMetricCollectionEnabled() {
+		log.Info("Gathering kmsg metrics")
+		if err := gatherKmsgMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering kmsg metrics")
+	}
+
+	if config.EDACMetricCollectionEnabled() {
+		log.Info("Gathering edac metrics")
+		if err := gatherEDACMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering edac metrics")
+	}
+
+	if config.NFSMetricCollectionEnabled() {
+		log.Info("Gathering nfs metrics")
+		if err := gatherNFSMetrics(); err != nil {
+			return err
+		}
+	} else {
+		log.Info("Not gathering nfs metrics")
+	}
+
+	return nil
+}
+
+// GetMetricsAsTimeSeries returns metrics as timeseries for remote write
+func GetMetricsAsTimeSeries(in []*dto.MetricFamily) []*prompb.TimeSeries {
+	var tsList []*prompb.TimeSeries
+
+	for i := range in {
+		metricName := *in[i].Name
+
+		// Extract name/value of other labels and set
+		for j := range in[i].Metric {
+			var labels []*prompb.Label
+			var ts prompb.TimeSeries
+			metricType := *in[i].Type
+			var metricValue float64
+
+			// Set __name__ label for metric name
+			labels = append(labels, &prompb.Label{
+				Name:  "__name__",
+				Value: metricName,
+			})
+
+			// For each label pair append
+			for k := range in[i].Metric[j].Label {
+				labels = append(labels, &prompb.Label{
+					Name:  *in[i].Metric[j].Label[k].Name,
+					Value: *in[i].Metric[j].Label[k].Value,
+				})
+			}
+
+			// Extract metric value
+			switch metricType {
+			case dto.MetricType_COUNTER:
+				metricValue = in[i].Metric[j].Counter.GetValue()
+			case dto.MetricType_GAUGE:
+				metricValue = in[i].Metric[j].Gauge.GetValue()
+			case dto.MetricType_HISTOGRAM:
+				metricValue = in[i].Metric[j].Histogram.GetSampleSum()
+			case dto.MetricType_SUMMARY:
+				metricValue = in[i].Metric[j].Summary.GetSampleSum()
+			case dto.MetricType_UNTYPED:
+				metricValue = in[i].Metric[j].Untyped.GetValue()
+			}
+
+			sample := &prompb.Sample{
+				Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
+				Value:     metricValue,
+			}
+
+			ts.Labels = labels
+			ts.Samples = []*prompb.Sample{sample}
+
+			tsList = append(tsList, &ts)
+		}
+	}
+
+	return tsList
+}
+
+// ResetNICMetrics resets NIC metrics
+func ResetNICMetrics() {
+	nicBytes.Reset()
+	nicBytesTX.Reset()
+	nicBytesRX.Reset()
+	nicPackets.Reset()
+	nicPacketsTX.Reset()
+	nicPacketsRX.Reset()
+	nicErrors.Reset()
+	nicErrorsTX.Reset()
+	nicErrorsRX.Reset()
+	nicDrop.Reset()
+	nicDropTX.Reset()
+	nicDropRX.Reset()
+	nicFIFO.Reset()
+	nicFIFOTX.Reset()
+	nicFIFORX.Reset()
+	nicFrameRX.Reset()
+	nicCollsTX.Reset()
+	nicCompressed.Reset()
+	nicCompressedTX.Reset()
+	nicCompressedRX.Reset()
+	nicCarrierTX.Reset()
+	nicMulticastRX.Reset()
+}
+
+// ResetSMARTMetrics resets SMART metrics incase drives were swapped
+func ResetSMARTMetrics() {
+	smartPowerCycles.Reset()
+	smartPowerOnHours.Reset()
+	smartNvmeCriticalWarning.Reset()
+	smartNvmeTemperature.Reset()
+	smartNvmeAvailSpare.Reset()
+	smartNvmePercentUsed.Reset()
+	smartNvmeEnduranceCritWarning.Reset()
+	smartNvmeDataUnitsRead.Reset()
+	smartNvmeDataUnitsWritten.Reset()
+	smartNvmeHostReads.Reset()
+	smartNvmeHostWrites.Reset()
+	smartNvmeCtrlBusyTime.Reset()
+	smartNvmeUnsafeShutdowns.Reset()
+	smartNvmeMediaErrors.Reset()
+	smartNvmeNumErrLogEntries.Reset()
+	smartNvmeWarningTempTime.Reset()
+	smartNvmeCritCompTime.Reset()
+
+	smartSataPercentLifetimeRemain.Reset()
+	smartSataTotalLBAsWritten.Reset()
+	smartSataReallocateNANDBlkCnt.Reset()
+	smartSataOfflineUncorrectable.Reset()
+	smartSataRawReadErrorRate.Reset()
+	smartSataReportedUncorrect.Reset()
+	smartSataErrorCorrectionCount.Reset()
+	smartSataWriteErrorRate.Reset()
+	smartSataEraseFailCount.Reset()
+	smartSataProgramFailCount.Reset()
+	smartSataReallocatedEventCount.Reset()
+	smartSataHostProgramPageCount.Reset()
+	smartSataUnusedReserveNANDBlk.Reset()
+	smartSataAveBlockEraseCount.Reset()
+	smartSataSATAInterfacDownshift.Reset()
+	smartSataUnexpectPowerLossCt.Reset()
+	smartSataTemperatureCelsius.Reset()
+	smartSataCurrentPendingECCCnt.Reset()
+	smartSataUDMACRCErrorCount.Reset()
+	smartSataFTLProgramPageCount.Reset()
+	smartSataSuccessRAINRecovCnt.Reset()
+	smartSataPercentLifeRemaining.Reset()
+	smartSataReallocatedSectorCt.Reset()
+	smartSataAvailableReservdSpace.Reset()
+	smartSataEraseFailCountTotal.Reset()
+	smartSataPowerLossCapTest.Reset()
+	smartSataTotalLBAsRead.Reset()
+	smartSataReadSoftErrorRate.Reset()
+	smartSataEndtoEndError.Reset()
+	smartSataUncorrectableErrorCnt.Reset()
+	smartSataCRCErrorCount.Reset()
+	smartSataThermalThrottleStatus.Reset()
+	smartSataUnsafeShutdownCount.Reset()
+	smartSataProgramFailCountChip.Reset()
+	smartSataUsedRsvdBlkCntTot.Reset()
+	smartSataUnusedRsvdBlkCntTot.Reset()
+	smartSataProgramFailCntTotal.Reset()
+	smartSataCurrentPendingSector.Reset()
+	smartSataEndofLife.Reset()
+	smartSataHardwareECCRecovered.Reset()
+	smartSataSoftReadErrorRate.Reset()
+	smartSataMediaWearoutIndicator.Reset()
+	smartSataDataAddressMarkErrs.Reset()
+}
+
+// GatherMetadata gathers v_agent_version and v_vendor_check only, used instead of Gather when
+// vendor_check.on_mismatch is idle
+func GatherMetadata() error {
+	log := zap.L().Sugar()
+
+	if err := gatherMetadataMetrics(); err != nil {
+		if !errors.Is(err, config.ErrLabelNotExist) {
+			return err
+		} else {
+			log.Warn(err)
+		}
+	}
+
+	return nil
+}
+
+func gatherMetadataMetrics() error {
+	version := config.GetVersion()
+
+	vAgentVersion.WithLabelValues(version).Set(0)
+
+	if config.VendorCheckEnabled() {
+		vendor, matched := config.GetVendorCheckResult()
+
+		v := 0.0
+		if matched {
+			v = 1
+		}
+
+		vVendorCheck.WithLabelValues(vendor, config.GetVendorCheckOnMismatch()).Set(v)
+	}
+
+	return nil
+}
+
+func gatherLoadavgMetrics() error {
+	loadavg, err := getLoadavg()
+	if err != nil {
+		return err
+	}
+
+	loadavgLoad1.WithLabelValues().Set(loadavg.Load1)
+	loadavgLoad5.WithLabelValues().Set(loadavg.Load5)
+	loadavgLoad15.WithLabelValues().Set(loadavg.Load15)
+	loadavgTasksRunning.WithLabelValues().Set(float64(loadavg.TasksRunning))
+	loadavgTasksTotal.WithLabelValues().Set(float64(loadavg.TasksTotal))
+
+	return nil
+}
+
+func gatherCPUMetrics() error {
+	// a single read so the aggregate, per core and counters come from the same snapshot
+	procStat, err := getProcStat()
+	if err != nil {
+		return err
+	}
+
+	cpuUtil := getCPUUtil(procStat)
+
+	cpuTotalTime := float64(cpuUtil.User + cpuUtil.Nice + cpuUtil.System + cpuUtil.Idle + cpuUtil.IOWait + cpuUtil.IRQ + cpuUtil.SoftIRQ + cpuUtil.Steal + cpuUtil.Guest + cpuUtil.GuestNice)
+
+	idleTime := float64(cpuUtil.Idle) / cpuTotalTime
+	inUseTime := 1 - idleTime
+	userTime := float64(cpuUtil.User) / cpuTotalTime
+	systemTime := float64(cpuUtil.System) / cpuTotalTime
+	iowaitTime := float64(cpuUtil.IOWait) / cpuTotalTime
+	irqTime := float64(cpuUtil.IRQ) / cpuTotalTime
+	sirqTime := float64(cpuUtil.SoftIRQ) / cpuTotalTime
+	stealTime := float64(cpuUtil.Steal) / cpuTotalTime
+	guestTime := float64(cpuUtil.Guest) / cpuTotalTime
+	guestNiceTime := float64(cpuUtil.GuestNice) / cpuTotalTime
+	niceTime := float64(cpuUtil.Nice) / cpuTotalTime
+
+	cpuCores.WithLabelValues().Set(float64(getHostCPUs()))
+	cpuUtilPct.WithLabelValues().Set(inUseTime * float64(100))          //nolint
+	cpuIdlePct.WithLabelValues().Set(idleTime * float64(100))           //nolint
+	cpuUserPct.WithLabelValues().Set(userTime * float64(100))           //nolint
+	cpuSystemPct.WithLabelValues().Set(systemTime * float64(100))       //nolint
+	cpuIOWaitPct.WithLabelValues().Set(iowaitTime * float64(100))       //nolint
+	cpuIRQPct.WithLabelValues().Set(irqTime * float64(100))             //nolint
+	cpuSoftIRQPct.WithLabelValues().Set(sirqTime * float64(100))        //nolint
+	cpuStealPct.WithLabelValues().Set(stealTime * float64(100))         //nolint
+	cpuGuestPct.WithLabelValues().Set(guestTime * float64(100))         //nolint
+	cpuGuestNicePct.WithLabelValues().Set(guestNiceTime * float64(100)) //nolint
+	cpuNicePct.WithLabelValues().Set(niceTime * float64(100))           //nolint
+
+	cpuContextSwitches.WithLabelValues().Set(float64(procStat.ContextSwitches))
+	cpuInterrupts.WithLabelValues().Set(float64(procStat.Interrupts))
+	cpuForks.WithLabelValues().Set(float64(procStat.Forks))
+	cpuProcsRunning.WithLabelValues().Set(float64(procStat.ProcsRunning))
+	cpuProcsBlocked.WithLabelValues().Set(float64(procStat.ProcsBlocked))
+	cpuBootTime.WithLabelValues().Set(float64(procStat.BootTime))
+
+	if !config.CPUPerCPUMetricCollectionEnabled() {
+		return nil
+	}
+
+	perCPU := getPerCPUUtil(procStat)
+
+	// cores can go offline, don't keep stale series around
+	cpuCoreUtilPct.Reset()
+	cpuCoreModePct.Reset()
+
+	for i := range perCPU {
+		total := float64(perCPU[i].User + perCPU[i].Nice + perCPU[i].System + perCPU[i].Idle + perCPU[i].IOWait + perCPU[i].IRQ + perCPU[i].SoftIRQ + perCPU[i].Steal + perCPU[i].Guest + perCPU[i].GuestNice)
+		if total <= 0 {
+			continue
+		}
+
+		modes := map[string]int{
+			"user":       perCPU[i].User,
+			"nice":       perCPU[i].Nice,
+			"system":     perCPU[i].System,
+			"idle":       perCPU[i].Idle,
+			"iowait":     perCPU[i].IOWait,
+			"irq":        perCPU[i].IRQ,
+			"softirq":    perCPU[i].SoftIRQ,
+			"steal":      perCPU[i].Steal,
+			"guest":      perCPU[i].Guest,
+			"guest_nice": perCPU[i].GuestNice,
+		}
+
+		for mode, v := range modes {
+			cpuCoreModePct.WithLabelValues(perCPU[i].CPU, mode).Set(float64(v) / total * float64(100)) //nolint
+		}
+
+		cpuCoreUtilPct.WithLabelValues(perCPU[i].CPU).Set((1 - float64(perCPU[i].Idle)/total) * float64(100)) //nolint
+	}
+
+	return nil
+}
+
+func gatherMemoryMetrics() error {
+	memory, err := getMeminfo()
+	if err != nil {
+		return err
+	}
+
+	memoryTotal.WithLabelValues().Set(float64(memory.MemTotal))
+	memoryFree.WithLabelValues().Set(float64(memory.MemFree))
+	memoryCached.WithLabelValues().Set(float64(memory.Cached))
+	memoryBuffered.WithLabelValues().Set(float64(memory.Buffers))
+	memorySwapTotal.WithLabelValues().Set(float64(memory.SwapTotal))
+	memorySwapFree.WithLabelValues().Set(float64(memory.SwapFree))
+
+	if memory.MemTotal > 0 {
+		memoryAvailablePct.WithLabelValues().Set(float64(memory.MemAvailable) / float64(memory.MemTotal) * float64(100)) //nolint
+	}
+
+	for field, v := range memory.Fields {
+		name := fmt.Sprintf("v_memory_%s", sanitizeMetricName(field))
+		if v.Bytes {
+			name = fmt.Sprintf("%s_bytes", name)
+		}
+
+		getDynamicGaugeVec(name, fmt.Sprintf("/proc/meminfo: %s", field), []string{}).WithLabelValues().Set(float64(v.Value))
+	}
+
+	return nil
+}
+
+func gatherNICMetrics() error {
+	nicStats, err := getFilteredNICStats()
+	if err != nil {
+		return err
+	}
+
+	// interfaces come and go (veth, etc), don't keep series for removed ones
+	ResetNICMetrics()
+
+	for i := range nicStats {
+		nicBytes.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Bytes))
+		nicBytesTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].BytesTX))
+		nicBytesRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].BytesRX))
+		nicPackets.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Packets))
+		nicPacketsTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].PacketsTX))
+		nicPacketsRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].PacketsRX))
+		nicErrors.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Errors))
+		nicErrorsTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].ErrorsTX))
+		nicErrorsRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].ErrorsRX))
+		nicDrop.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Drop))
+		nicDropTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].DropTX))
+		nicDropRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].DropRX))
+		nicFIFO.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FIFO))
+		nicFIFOTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FIFOTX))
+		nicFIFORX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FIFORX))
+		nicFrameRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FrameRX))
+		nicCollsTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CollsTX))
+		nicCompressed.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Compressed))
+		nicCompressedTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CompressedTX))
+		nicCompressedRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CompressedRX))
+		nicCarrierTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CarrierTX))
+		nicMulticastRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].MulticastRX))
+	}
+
+	return nil
+}
+
+func gatherDiskMetrics() error {
+	diskStats, err := getDiskStatsUtil()
+	if err != nil {
+		return err
+	}
+
+	// devices come and go (hotplug, detached volumes), don't keep reporting stale ones
+	for _, vec := range []*prometheus.GaugeVec{
+		diskStatsReads, diskStatsReadsMerged, diskStatsSectorsRead, diskStatsMillisecondsReading,
+		diskStatsWritesCompleted, diskStatsWritesMerged, diskStatsSectorsWritten, diskStatsMillisecondsWriting,
+		diskStatsIOsInProgress, diskStatsMillisecondsInIOs, diskStatsWeightedIOsInMS,
+		diskStatsDiscards, diskStatsDiscardsMerged, diskStatsSectorsDiscarded, diskStatsMillisecondsDiscarding,
+		diskReadBytes, diskWriteBytes, diskReadIOPS, diskWriteIOPS, diskReadAwait, diskWriteAwait, diskQueueDepth, diskUtil, diskInfo,
+	} {
+		vec.Reset()
+	}
+
+	for i := range diskStats {
+		id := diskStats[i].ID
+		if id == "" {
+			id = diskStats[i].Device
+		}
+
+		labels := []string{diskStats[i].Device}
+		if config.GetDiskStatsByID() {
+			labels = []string{id}
+		}
+
+		diskInfo.WithLabelValues(id, diskStats[i].Device).Set(1)
+
+		diskStatsReads.WithLabelValues(labels...).Set(float64(diskStats[i].Reads))
+		diskStatsReadsMerged.WithLabelValues(labels...).Set(float64(diskStats[i].ReadsMerged))
+		diskStatsSectorsRead.WithLabelValues(labels...).Set(float64(diskStats[i].SectorsRead))
+		diskStatsMillisecondsReading.WithLabelValues(labels...).Set(float64(diskStats[i].MillisecondsReading))
+		diskStatsWritesCompleted.WithLabelValues(labels...).Set(float64(diskStats[i].WritesCompleted))
+		diskStatsWritesMerged.WithLabelValues(labels...).Set(float64(diskStats[i].WritesMerged))
+		diskStatsSectorsWritten.WithLabelValues(labels...).Set(float64(diskStats[i].SectorsWritten))
+		diskStatsMillisecondsWriting.WithLabelValues(labels...).Set(float64(diskStats[i].MillisecondsWriting))
+		diskStatsIOsInProgress.WithLabelValues(labels...).Set(float64(diskStats[i].IOsInProgress))
+		diskStatsMillisecondsInIOs.WithLabelValues(labels...).Set(float64(diskStats[i].MillisecondsInIOs))
+		diskStatsWeightedIOsInMS.WithLabelValues(labels...).Set(float64(diskStats[i].WeightedIOsInMS))
+		diskStatsDiscards.WithLabelValues(labels...).Set(float64(diskStats[i].Discards))
+		diskStatsDiscardsMerged.WithLabelValues(labels...).Set(float64(diskStats[i].DiscardsMerged))
+		diskStatsSectorsDiscarded.WithLabelValues(labels...).Set(float64(diskStats[i].SectorsDiscarded))
+		diskStatsMillisecondsDiscarding.WithLabelValues(labels...).Set(float64(diskStats[i].MillisecondsDiscarding))
+
+		diskReadBytes.WithLabelValues(labels...).Set(diskStats[i].ReadBytes)
+		diskWriteBytes.WithLabelValues(labels...).Set(diskStats[i].WriteBytes)
+		diskReadIOPS.WithLabelValues(labels...).Set(diskStats[i].ReadIOPS)
+		diskWriteIOPS.WithLabelValues(labels...).Set(diskStats[i].WriteIOPS)
+		diskReadAwait.WithLabelValues(labels...).Set(diskStats[i].ReadAwait)
+		diskWriteAwait.WithLabelValues(labels...).Set(diskStats[i].WriteAwait)
+		diskQueueDepth.WithLabelValues(labels...).Set(diskStats[i].QueueDepth)
+		diskUtil.WithLabelValues(labels...).Set(diskStats[i].Util)
+	}
+
+	return nil
+}
+
+func gatherFilesystemMetrics() error {
+	fsStats, err := getFilesystemUtil()
+	if err != nil {
+		return err
+	}
+
+	// mounts come and go, don't keep reporting stale ones
+	for _, vec := range []*prometheus.GaugeVec{fsInodes, fsInodesUsed, fsInodesUtil, fsBytes, fsBytesUsed, fsBytesUtil, fsReadOnly, fsDeviceError} {
+		vec.Reset()
+	}
+
+	for i := range fsStats {
+		labels := []string{fsStats[i].Device, fsStats[i].Mount, fsStats[i].Type}
+
+		if fsStats[i].ReadOnly {
+			fsReadOnly.WithLabelValues(labels...).Set(1)
+		} else {
+			fsReadOnly.WithLabelValues(labels...).Set(0)
+		}
+
+		if fsStats[i].Error {
+			fsDeviceError.WithLabelValues(labels...).Set(1)
+
+			continue
+		}
+
+		fsDeviceError.WithLabelValues(labels...).Set(0)
+		fsInodes.WithLabelValues(labels...).Set(float64(fsStats[i].Inodes))
+		fsInodesUsed.WithLabelValues(labels...).Set(float64(fsStats[i].InodesUsed))
+		fsInodesUtil.WithLabelValues(labels...).Set(fsStats[i].InodesUtil)
+		fsBytes.WithLabelValues(labels...).Set(float64(fsStats[i].BytesTotal))
+		fsBytesUsed.WithLabelValues(labels...).Set(float64(fsStats[i].BytesUsed))
+		fsBytesUtil.WithLabelValues(labels...).Set(fsStats[i].BytesUtil)
+	}
+
+	return nil
+}
+
+func gatherKubernetesMetrics() error {
+	log := zap.L().Sugar()
+
+	if err := DoKubeAPIServerHealthCheck(); err != nil {
+		log.Error(err)
+
+		kubeAPIServerHealthz.WithLabelValues().Set(float64(1))
+	} else {
+		kubeAPIServerHealthz.WithLabelValues().Set(float64(0))
+	}
+
+	if err := ScrapeKubeAPIServerMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherKonnectivityMetrics() error {
+	log := zap.L().Sugar()
+
+	if err := DoKonnectivityHealthCheck(); err != nil {
+		log.Error(err)
+
+		konnectivityHealthz.WithLabelValues().Set(float64(1))
+	} else {
+		konnectivityHealthz.WithLabelValues().Set(float64(0))
+	}
+
+	if err := ScrapeKonnectivityMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherEtcdMetrics() error {
+	log := zap.L().Sugar()
+
+	if err := DoEtcdHealthCheck(); err != nil {
+		log.Error(err)
+
+		etcdServerHealth.WithLabelValues().Set(float64(1))
+	} else {
+		etcdServerHealth.WithLabelValues().Set(float64(0))
+	}
+
+	if err := ScrapeEtcdMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherNginxVTSMetrics() error {
+	log := zap.L().Sugar()
+
+	if err := DoNginxVTSHealthCheck(); err != nil {
+		log.Error(err)
+
+		nginxVtsHealthy.WithLabelValues().Set(float64(1))
+	} else {
+		nginxVtsHealthy.WithLabelValues().Set(float64(0))
+	}
+
+	if err := ScrapeNginxVTSMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherVCDNAgentMetrics() error {
+	log := zap.L().Sugar()
+
+	if err := DoVCDNAgentHealthCheck(); err != nil {
+		log.Error(err)
+
+		vcdnAgentHealth.WithLabelValues().Set(float64(1))
+	} else {
+		vcdnAgentHealth.WithLabelValues().Set(float64(0))
+	}
+
+	if err := ScrapeVCDNAgentMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherHAProxyMetrics() error {
+	log := zap.L().Sugar()
+
+	if err := DoHAProxyHealthCheck(); err != nil {
+		log.Error(err)
+
+		haproxyHealthy.WithLabelValues().Set(float64(1))
+	} else {
+		haproxyHealthy.WithLabelValues().Set(float64(0))
+	}
+
+	if err := ScrapeHAProxyMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherCephMetrics() error {
+	if err := ScrapeCephMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherVDNSMetrics() error {
+	log := zap.L().Sugar()
+
+	if err := DoVDNSHealthCheck(); err != nil {
+		log.Error(err)
+
+		vdnsHealthy.WithLabelValues().Set(float64(1))
+	} else {
+		vdnsHealthy.WithLabelValues().Set(float64(0))
+	}
+
+	if err := ScrapeVDNSMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherScrapeablePodsMetrics() error {
+	if err := ScrapeKubernetesPods(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherSMARTmetrics() error {
+	ResetSMARTMetrics()
+
+	if err := ScrapeSMARTMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherDCGMmetrics() error {
+	if err := ScrapeDCGMMetrics(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func gatherVMStatMetrics() error {
+	vmstat, err := getVMStat()
+	if err != nil {
+		return err
+	}
+
+	for field, v := range vmstat {
+		name := fmt.Sprintf("v_vmstat_%s", sanitizeMetricName(field))
+
+		getDynamicGaugeVec(name, fmt.Sprintf("/proc/vmstat: %s", field), []string{}).WithLabelValues().Set(float64(v))
+	}
+
+	return nil
+}
+
+func gatherNetStatMetrics() error {
+	netStats, err := getNetStats()
+	if err != nil {
+		return err
+	}
+
+	for field, v := range netStats {
+		name := fmt.Sprintf("v_netstat_%s", sanitizeMetricName(field))
+
+		getDynamicGaugeVec(name, fmt.Sprintf("/proc/net/{snmp,snmp6,netstat}: %s", field), []string{}).WithLabelValues().Set(v)
+	}
+
+	sockStats, err := getSockStats()
+	if err != nil {
+		return err
+	}
+
+	for field, v := range sockStats {
+		name := fmt.Sprintf("v_sockstat_%s", sanitizeMetricName(field))
+
+		getDynamicGaugeVec(name, fmt.Sprintf("/proc/net/sockstat{,6}: %s", field), []string{}).WithLabelValues().Set(v)
+	}
+
+	return nil
+}
+
+func gatherConntrackMetrics() error {
+	log := zap.L().Sugar()
+
+	conntrack, err := getConntrack()
+	if err != nil {
+		// module loads on first use of conntrack (iptables/nftables rule), nothing to report until then
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warnf("conntrack: nf_conntrack not loaded: %s", err)
+
+			return nil
+		}
+
+		return err
+	}
+
+	conntrackEntries.WithLabelValues().Set(conntrack.Entries)
+	conntrackEntriesLimit.WithLabelValues().Set(conntrack.EntriesLimit)
+
+	for i := range conntrack.Stats {
+		for field, v := range conntrack.Stats[i].Fields {
+			// same as v_conntrack_entries, not per cpu
+			if field == "entries" {
+				continue
+			}
+
+			name := fmt.Sprintf("v_conntrack_stat_%s", sanitizeMetricName(field))
+
+			getDynamicGaugeVec(name, fmt.Sprintf("/proc/net/stat/nf_conntrack: %s", field), []string{"cpu"}).WithLabelValues(conntrack.Stats[i].CPU).Set(float64(v))
+		}
+	}
+
+	return nil
+}
+
+func gatherSoftnetMetrics() error {
+	softnet, err := getSoftnetStats()
+	if err != nil {
+		return err
+	}
+
+	for i := range softnet {
+		softnetProcessed.WithLabelValues(softnet[i].CPU).Set(float64(softnet[i].Processed))
+		softnetDropped.WithLabelValues(softnet[i].CPU).Set(float64(softnet[i].Dropped))
+		softnetTimeSqueeze.WithLabelValues(softnet[i].CPU).Set(float64(softnet[i].TimeSqueeze))
+		softnetReceivedRPS.WithLabelValues(softnet[i].CPU).Set(float64(softnet[i].ReceivedRPS))
+		softnetFlowLimitCount.WithLabelValues(softnet[i].CPU).Set(float64(softnet[i].FlowLimitCount))
+		softnetBacklogLen.WithLabelValues(softnet[i].CPU).Set(float64(softnet[i].BacklogLen))
+	}
+
+	return nil
+}
+
+func gatherTCPStatesMetrics() error {
+	tcpStateCounts, err := getTCPStates(config.GetTCPStatesPorts())
+	if err != nil {
+		return err
+	}
+
+	// states with no sockets report 0 rather than disappearing
+	for _, family := range []string{"ipv4", "ipv6"} {
+		for _, state := range tcpStates {
+			tcpConnections.WithLabelValues(family, state).Set(0)
+		}
+	}
+
+	tcpConnectionsPort.Reset()
+
+	for i := range tcpStateCounts {
+		if tcpStateCounts[i].Port == 0 {
+			tcpConnections.WithLabelValues(tcpStateCounts[i].Family, tcpStateCounts[i].State).Set(float64(tcpStateCounts[i].Count))
+		} else {
+			tcpConnectionsPort.WithLabelValues(tcpStateCounts[i].Family, tcpStateCounts[i].State, strconv.Itoa(int(tcpStateCounts[i].Port))).Set(float64(tcpStateCounts[i].Count))
+		}
+	}
+
+	return nil
+}
+
+func gatherEthtoolMetrics() error {
+	ethtoolStats, err := getEthtoolStats()
+	if err != nil {
+		return err
+	}
+
+	// interfaces come and go, operstate and driver info change
+	for _, vec := range []*prometheus.GaugeVec{nicLinkSpeed, nicLinkFullDuplex, nicCarrier, nicMTU, nicOperState, ethtoolInfo} {
+		vec.Reset()
+	}
+
+	resetDynamicGaugeVecs("v_ethtool_stat_")
+
+	for i := range ethtoolStats {
+		duplex := float64(-1)
+		switch ethtoolStats[i].Duplex {
+		case "full":
+			duplex = 1
+		case "half":
+			duplex = 0
+		}
+
+		nicLinkSpeed.WithLabelValues(ethtoolStats[i].Interface).Set(ethtoolStats[i].SpeedMbps)
+		nicLinkFullDuplex.WithLabelValues(ethtoolStats[i].Interface).Set(duplex)
+		nicCarrier.WithLabelValues(ethtoolStats[i].Interface).Set(ethtoolStats[i].Carrier)
+		nicMTU.WithLabelValues(ethtoolStats[i].Interface).Set(ethtoolStats[i].MTU)
+		nicOperState.WithLabelValues(ethtoolStats[i].Interface, ethtoolStats[i].OperState).Set(1)
+
+		if ethtoolStats[i].Driver != "" {
+			ethtoolInfo.WithLabelValues(ethtoolStats[i].Interface, ethtoolStats[i].Driver, ethtoolStats[i].DriverVersion, ethtoolStats[i].FirmwareVersion, ethtoolStats[i].BusInfo).Set(1)
+		}
+
+		for stat, v := range ethtoolStats[i].Stats {
+			name := fmt.Sprintf("v_ethtool_stat_%s", sanitizeMetricName(stat))
+
+			getDynamicGaugeVec(name, fmt.Sprintf("ethtool -S: %s", stat), []string{"nic"}).WithLabelValues(ethtoolStats[i].Interface).Set(float64(v))
+		}
+	}
+
+	return nil
+}
+
+// includeExcludeFilter matches names against an include and exclude regex, empty regexes are ignored
+type includeExcludeFilter struct {
+	include *regexp.Regexp
+	exclude *regexp.Regexp
+}
+
+func newIncludeExcludeFilter(include, exclude string) (*includeExcludeFilter, error) {
+	var f includeExcludeFilter
+	var err error
+
+	if include != "" {
+		if f.include, err = regexp.Compile(include); err != nil {
+			return nil, err
+		}
+	}
+
+	if exclude != "" {
+		if f.exclude, err = regexp.Compile(exclude); err != nil {
+			return nil, err
+		}
+	}
+
+	return &f, nil
+}
+
+// match returns true if name matches include (or include is empty) and doesn't match exclude
+func (f *includeExcludeFilter) match(name string) bool {
+	if f.include != nil && !f.include.MatchString(name) {
+		return false
+	}
+
+	if f.exclude != nil && f.exclude.MatchString(name) {
+		return false
 	}
 
-	if config.MemoryMetricCollectionEnabled() {
-		log.Info("Gathering memory metrics")
-		if err := gatherMemoryMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering memory metrics")
+	return true
+}
+
+func gatherHwmonMetrics() error {
+	sensors, err := getHwmonSensors(sysClassHwmonPath)
+	if err != nil {
+		return err
 	}
 
-	if config.NICMetricCollectionEnabled() {
-		log.Info("Gathering nic metrics")
-		if err := gatherNICMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering nic metrics")
+	zones, coolingDevices, err := getThermalZones(sysClassThermalPath)
+	if err != nil {
+		return err
 	}
 
-	if config.DiskStatsMetricCollectionEnabled() {
-		log.Info("Gathering disk stats metrics")
-		if err := gatherDiskMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering disk stats metrics")
+	for _, vec := range []*prometheus.GaugeVec{hwmonTemp, hwmonTempCrit, hwmonFan, hwmonIn, hwmonPower, hwmonAlarm, thermalZoneTemp, thermalCoolingCurState, thermalCoolingMaxState} {
+		vec.Reset()
 	}
 
-	if config.FileSystemMetricCollectionEnabled() {
-		log.Info("Gathering file system metrics")
-		if err := gatherFilesystemMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering file system metrics")
+	gauges := map[string]*prometheus.GaugeVec{
+		"temp":  hwmonTemp,
+		"fan":   hwmonFan,
+		"in":    hwmonIn,
+		"power": hwmonPower,
 	}
 
-	if config.KubernetesMetricCollectionEnabled() {
-		log.Info("Gathering kubernetes metrics")
-		if err := gatherKubernetesMetrics(); err != nil {
-			return err
+	for i := range sensors {
+		labels := []string{sensors[i].Chip, sensors[i].ChipName, sensors[i].Sensor, sensors[i].Label}
+
+		if sensors[i].HasValue {
+			gauges[sensors[i].Type].WithLabelValues(labels...).Set(sensors[i].Value)
 		}
-	} else {
-		log.Info("Not gathering kubernetes metrics")
-	}
 
-	if config.KonnectivityMetricCollectionEnabled() {
-		log.Info("Gathering konnectivity metrics")
-		if err := gatherKonnectivityMetrics(); err != nil {
-			return err
+		if sensors[i].HasCrit {
+			hwmonTempCrit.WithLabelValues(labels...).Set(sensors[i].Crit)
 		}
-	} else {
-		log.Info("Not gathering konnectivity metrics")
-	}
 
-	if config.EtcdMetricCollectionEnabled() {
-		log.Info("Gathering etcd metrics")
-		if err := gatherEtcdMetrics(); err != nil {
-			return err
+		if sensors[i].HasAlarm {
+			hwmonAlarm.WithLabelValues(append(labels, sensors[i].Type)...).Set(sensors[i].Alarm)
 		}
-	} else {
-		log.Info("Not gathering etcd metrics")
 	}
 
-	if config.NginxVTSMetricsCollectionEnabled() {
-		log.Info("Gathering nginx-vts metrics")
-		if err := gatherNginxVTSMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering nginx-vts metrics")
+	for i := range zones {
+		thermalZoneTemp.WithLabelValues(zones[i].Zone, zones[i].Type).Set(zones[i].Temp)
 	}
 
-	if config.VCDNAgentMetricsCollectionEnabled() {
-		log.Info("Gathering v-cdn-agent metrics")
-		if err := gatherVCDNAgentMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering v-cdn-agent metrics")
+	for i := range coolingDevices {
+		thermalCoolingCurState.WithLabelValues(coolingDevices[i].Device, coolingDevices[i].Type).Set(coolingDevices[i].CurState)
+		thermalCoolingMaxState.WithLabelValues(coolingDevices[i].Device, coolingDevices[i].Type).Set(coolingDevices[i].MaxState)
 	}
 
-	if config.HAProxyMetricCollectionEnabled() {
-		log.Info("Gathering haproxy metrics")
-		if err := gatherHAProxyMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering haproxy metrics")
+	return nil
+}
+
+func gatherCPUFreqMetrics() error {
+	cpuFreqStats, err := getCPUFreqStats(sysDevicesCPUPath)
+	if err != nil {
+		return err
 	}
 
-	if config.CephMetricCollectionEnabled() {
-		log.Info("Gathering ceph metrics")
-		if err := gatherCephMetrics(); err != nil {
-			return err
-		}
-	} else {
-		log.Info("Not gathering ceph metrics")
+	// cpus can be hotplugged and governors change
+	for _, vec := range []*prometheus.GaugeVec{cpuFreqCur, cpuFreqMin, cpuFreqMax, cpuFreqGovernor, cpuCoreThrottles, cpuCoreThrottleTime, cpuPackageThrottles, cpuPackageThrottleTime} {
+		vec.Reset()
 	}
 
-	if config.VDNSMetricsCollectionEnabled() {
-		log.Info("Gathering v-dns metrics")
-		if err := gatherVDNSMetrics(); err != nil {
-			return err
+	for i := range cpuFreqStats {
+		cs := cpuFreqStats[i]
+
+		// VMs usually don't expose cpufreq or thermal_throttle
+		if cs.CurHz >= 0 {
+			cpuFreqCur.WithLabelValues(cs.CPU).Set(cs.CurHz)
+			cpuFreqMin.WithLabelValues(cs.CPU).Set(cs.MinHz)
+			cpuFreqMax.WithLabelValues(cs.CPU).Set(cs.MaxHz)
 		}
-	} else {
-		log.Info("Not gathering v-dns metrics")
-	}
 
-	if config.KubernetesPodsCollectionEnabled() {
-		log.Info("Gathering kubernetes_pods metrics")
-		if err := gatherScrapeablePodsMetrics(); err != nil {
-			return err
+		if cs.Governor != "" {
+			cpuFreqGovernor.WithLabelValues(cs.CPU, cs.Governor).Set(1)
 		}
-	} else {
-		log.Info("Not gathering kubernetes_pods metrics")
-	}
 
-	if config.SMARTCollectionEnabled() {
-		log.Info("Gathering SMART metrics")
-		if err := gatherSMARTmetrics(); err != nil {
-			return err
+		if cs.CoreThrottles >= 0 {
+			cpuCoreThrottles.WithLabelValues(cs.CPU).Set(cs.CoreThrottles)
+			cpuCoreThrottleTime.WithLabelValues(cs.CPU).Set(cs.CoreThrottleTimeMS)
 		}
-	} else {
-		log.Info("Not gathering SMART metrics")
-	}
 
-	if config.DCGMCollectionEnabled() {
-		log.Info("Gathering DCGM metrics")
-		if err := gatherDCGMmetrics(); err != nil {
-			return err
+		if cs.PackageThrottles >= 0 {
+			cpuPackageThrottles.WithLabelValues(cs.Package).Set(cs.PackageThrottles)
+			cpuPackageThrottleTime.WithLabelValues(cs.Package).Set(cs.PackageThrottleTimeMS)
 		}
-	} else {
-		log.Info("Not gathering DCGM metrics")
 	}
 
 	return nil
 }
 
-// GetMetricsAsTimeSeries returns metrics as timeseries for remote write
-func GetMetricsAsTimeSeries(in []*dto.MetricFamily) []*prompb.TimeSeries {
-	var tsList []*prompb.TimeSeries
+func gatherMDAdmMetrics() error {
+	mdStats, err := getMDStats(procMDStatPath, sysBlockPath)
+	if err != nil {
+		return err
+	}
 
-	for i := range in {
-		metricName := *in[i].Name
+	// state and sync action are labels, arrays can be stopped
+	for _, vec := range []*prometheus.GaugeVec{mdState, mdDisks, mdDisksRequired, mdDegraded, mdSyncAction, mdSyncProgress, mdSyncFinish, mdSyncSpeed} {
+		vec.Reset()
+	}
 
-		// Extract name/value of other labels and set
-		for j := range in[i].Metric {
-			var labels []*prompb.Label
-			var ts prompb.TimeSeries
-			metricType := *in[i].Type
-			var metricValue float64
+	for i := range mdStats {
+		md := mdStats[i]
+
+		mdState.WithLabelValues(md.Device, md.Level, md.State).Set(1)
+		mdDisks.WithLabelValues(md.Device, "active").Set(float64(md.Active))
+		mdDisks.WithLabelValues(md.Device, "failed").Set(float64(md.Failed))
+		mdDisks.WithLabelValues(md.Device, "spare").Set(float64(md.Spare))
+		mdDisksRequired.WithLabelValues(md.Device).Set(float64(md.Disks))
+		mdDegraded.WithLabelValues(md.Device).Set(float64(md.Degraded))
+		mdSyncAction.WithLabelValues(md.Device, md.SyncAction).Set(1)
+		mdSyncProgress.WithLabelValues(md.Device).Set(md.SyncPct)
+		mdSyncFinish.WithLabelValues(md.Device).Set(md.SyncFinish)
+		mdSyncSpeed.WithLabelValues(md.Device).Set(md.SyncSpeed)
+	}
 
-			// Set __name__ label for metric name
-			labels = append(labels, &prompb.Label{
-				Name:  "__name__",
-				Value: metricName,
-			})
+	return nil
+}
 
-			// For each label pair append
-			for k := range in[i].Metric[j].Label {
-				labels = append(labels, &prompb.Label{
-					Name:  *in[i].Metric[j].Label[k].Name,
-					Value: *in[i].Metric[j].Label[k].Value,
-				})
-			}
+func gatherProcessesMetrics() error {
+	processGroups, err := getProcessGroupStats()
+	if err != nil {
+		return err
+	}
 
-			// Extract metric value
-			switch metricType {
-			case dto.MetricType_COUNTER:
-				metricValue = in[i].Metric[j].Counter.GetValue()
-			case dto.MetricType_GAUGE:
-				metricValue = in[i].Metric[j].Gauge.GetValue()
-			case dto.MetricType_HISTOGRAM:
-				metricValue = in[i].Metric[j].Histogram.GetSampleSum()
-			case dto.MetricType_SUMMARY:
-				metricValue = in[i].Metric[j].Summary.GetSampleSum()
-			case dto.MetricType_UNTYPED:
-				metricValue = in[i].Metric[j].Untyped.GetValue()
-			}
+	for i := range processGroups {
+		pg := processGroups[i]
 
-			sample := &prompb.Sample{
-				Timestamp: time.Now().UnixNano() / int64(time.Millisecond),
-				Value:     metricValue,
-			}
+		running := 0.0
+		if pg.Count > 0 {
+			running = 1
+		}
 
-			ts.Labels = labels
-			ts.Samples = []*prompb.Sample{sample}
+		processGroupCount.WithLabelValues(pg.Group).Set(float64(pg.Count))
+		processGroupRunning.WithLabelValues(pg.Group).Set(running)
+		processGroupThreads.WithLabelValues(pg.Group).Set(float64(pg.Threads))
+		processGroupRSS.WithLabelValues(pg.Group).Set(float64(pg.RSSBytes))
+		processGroupOpenFDs.WithLabelValues(pg.Group).Set(float64(pg.OpenFDs))
+		processGroupCPUUser.WithLabelValues(pg.Group).Set(pg.CPUUserSeconds)
+		processGroupCPUSystem.WithLabelValues(pg.Group).Set(pg.CPUSystemSeconds)
+		processGroupStartTime.WithLabelValues(pg.Group).Set(pg.StartTime)
+		processGroupRestarts.WithLabelValues(pg.Group).Set(float64(pg.Restarts))
+	}
 
-			tsList = append(tsList, &ts)
+	return nil
+}
+
+func gatherCgroupsMetrics() error {
+	log := zap.L().Sugar()
+
+	cgroupStats, err := getCgroupStats()
+	if err != nil {
+		// cgroup v1 or hybrid hierarchy
+		if errors.Is(err, os.ErrNotExist) {
+			log.Warn(err)
+
+			return nil
 		}
+
+		return err
 	}
 
-	return tsList
-}
+	// containers and services come and go
+	for _, vec := range []*prometheus.GaugeVec{
+		cgroupCPUUsage, cgroupCPUUser, cgroupCPUSystem, cgroupCPUPeriods, cgroupCPUThrottledPeriods, cgroupCPUThrottled, cgroupMemoryCurrent, cgroupMemoryMax, cgroupMemoryEvents, cgroupIORead, cgroupIOWrite, cgroupIOReads, cgroupIOWrites, cgroupPidsCurrent, cgroupPidsMax,
+	} {
+		vec.Reset()
+	}
 
-// ResetSMARTMetrics resets SMART metrics incase drives were swapped
-func ResetSMARTMetrics() {
-	smartPowerCycles.Reset()
-	smartPowerOnHours.Reset()
-	smartNvmeCriticalWarning.Reset()
-	smartNvmeTemperature.Reset()
-	smartNvmeAvailSpare.Reset()
-	smartNvmePercentUsed.Reset()
-	smartNvmeEnduranceCritWarning.Reset()
-	smartNvmeDataUnitsRead.Reset()
-	smartNvmeDataUnitsWritten.Reset()
-	smartNvmeHostReads.Reset()
-	smartNvmeHostWrites.Reset()
-	smartNvmeCtrlBusyTime.Reset()
-	smartNvmeUnsafeShutdowns.Reset()
-	smartNvmeMediaErrors.Reset()
-	smartNvmeNumErrLogEntries.Reset()
-	smartNvmeWarningTempTime.Reset()
-	smartNvmeCritCompTime.Reset()
+	usec := float64(time.Second / time.Microsecond)
 
-	smartSataPercentLifetimeRemain.Reset()
-	smartSataTotalLBAsWritten.Reset()
-	smartSataReallocateNANDBlkCnt.Reset()
-	smartSataOfflineUncorrectable.Reset()
-	smartSataRawReadErrorRate.Reset()
-	smartSataReportedUncorrect.Reset()
-	smartSataErrorCorrectionCount.Reset()
-	smartSataWriteErrorRate.Reset()
-	smartSataEraseFailCount.Reset()
-	smartSataProgramFailCount.Reset()
-	smartSataReallocatedEventCount.Reset()
-	smartSataHostProgramPageCount.Reset()
-	smartSataUnusedReserveNANDBlk.Reset()
-	smartSataAveBlockEraseCount.Reset()
-	smartSataSATAInterfacDownshift.Reset()
-	smartSataUnexpectPowerLossCt.Reset()
-	smartSataTemperatureCelsius.Reset()
-	smartSataCurrentPendingECCCnt.Reset()
-	smartSataUDMACRCErrorCount.Reset()
-	smartSataFTLProgramPageCount.Reset()
-	smartSataSuccessRAINRecovCnt.Reset()
-	smartSataPercentLifeRemaining.Reset()
-	smartSataReallocatedSectorCt.Reset()
-	smartSataAvailableReservdSpace.Reset()
-	smartSataEraseFailCountTotal.Reset()
-	smartSataPowerLossCapTest.Reset()
-	smartSataTotalLBAsRead.Reset()
-	smartSataReadSoftErrorRate.Reset()
-	smartSataEndtoEndError.Reset()
-	smartSataUncorrectableErrorCnt.Reset()
-	smartSataCRCErrorCount.Reset()
-	smartSataThermalThrottleStatus.Reset()
-	smartSataUnsafeShutdownCount.Reset()
-	smartSataProgramFailCountChip.Reset()
-	smartSataUsedRsvdBlkCntTot.Reset()
-	smartSataUnusedRsvdBlkCntTot.Reset()
-	smartSataProgramFailCntTotal.Reset()
-	smartSataCurrentPendingSector.Reset()
-	smartSataEndofLife.Reset()
-	smartSataHardwareECCRecovered.Reset()
-	smartSataSoftReadErrorRate.Reset()
-	smartSataMediaWearoutIndicator.Reset()
-	smartSataDataAddressMarkErrs.Reset()
-}
+	for i := range cgroupStats {
+		cs := cgroupStats[i]
+		labels := []string{cs.Cgroup, cs.PodUID, cs.ContainerID}
+
+		cgroupCPUUsage.WithLabelValues(labels...).Set(float64(cs.CPU["usage_usec"]) / usec)
+		cgroupCPUUser.WithLabelValues(labels...).Set(float64(cs.CPU["user_usec"]) / usec)
+		cgroupCPUSystem.WithLabelValues(labels...).Set(float64(cs.CPU["system_usec"]) / usec)
+
+		// only present with the cpu controller enabled
+		if _, ok := cs.CPU["nr_periods"]; ok {
+			cgroupCPUPeriods.WithLabelValues(labels...).Set(float64(cs.CPU["nr_periods"]))
+			cgroupCPUThrottledPeriods.WithLabelValues(labels...).Set(float64(cs.CPU["nr_throttled"]))
+			cgroupCPUThrottled.WithLabelValues(labels...).Set(float64(cs.CPU["throttled_usec"]) / usec)
+		}
 
-func gatherMetadataMetrics() error {
-	version := config.GetVersion()
+		cgroupMemoryCurrent.WithLabelValues(labels...).Set(float64(cs.MemoryCurrent))
 
-	vAgentVersion.WithLabelValues(version).Set(0)
+		if cs.MemoryMax >= 0 {
+			cgroupMemoryMax.WithLabelValues(labels...).Set(float64(cs.MemoryMax))
+		}
+
+		for event, v := range cs.MemoryEvents {
+			cgroupMemoryEvents.WithLabelValues(append(labels, event)...).Set(float64(v))
+		}
+
+		for j := range cs.IO {
+			ioLabels := append(labels, cs.IO[j].Device) //nolint
+
+			cgroupIORead.WithLabelValues(ioLabels...).Set(float64(cs.IO[j].Fields["rbytes"]))
+			cgroupIOWrite.WithLabelValues(ioLabels...).Set(float64(cs.IO[j].Fields["wbytes"]))
+			cgroupIOReads.WithLabelValues(ioLabels...).Set(float64(cs.IO[j].Fields["rios"]))
+			cgroupIOWrites.WithLabelValues(ioLabels...).Set(float64(cs.IO[j].Fields["wios"]))
+		}
+
+		cgroupPidsCurrent.WithLabelValues(labels...).Set(float64(cs.PidsCurrent))
+
+		if cs.PidsMax >= 0 {
+			cgroupPidsMax.WithLabelValues(labels...).Set(float64(cs.PidsMax))
+		}
+	}
 
 	return nil
 }
 
-func gatherLoadavgMetrics() error {
-	loadavg, err := getLoadavg()
+func gatherSystemdMetrics() error {
+	log := zap.L().Sugar()
+
+	if !systemdAvailable() {
+		log.Warn("systemd: not running under systemd, skipping")
+
+		return nil
+	}
+
+	units, err := getSystemdUnits(config.GetSystemdUnits())
 	if err != nil {
 		return err
 	}
 
-	loadavgLoad1.WithLabelValues().Set(loadavg.Load1)
-	loadavgLoad5.WithLabelValues().Set(loadavg.Load5)
-	loadavgLoad15.WithLabelValues().Set(loadavg.Load15)
-	loadavgTasksRunning.WithLabelValues().Set(float64(loadavg.TasksRunning))
-	loadavgTasksTotal.WithLabelValues().Set(float64(loadavg.TasksTotal))
+	// sub state is a label
+	systemdUnitInfo.Reset()
+
+	for i := range units {
+		for _, state := range systemdActiveStates {
+			v := 0.0
+			if units[i].ActiveState == state {
+				v = 1
+			}
+
+			systemdUnitState.WithLabelValues(units[i].Unit, state).Set(v)
+		}
+
+		systemdUnitInfo.WithLabelValues(units[i].Unit, units[i].LoadState, units[i].SubState).Set(1)
+		systemdUnitActiveEnterTime.WithLabelValues(units[i].Unit).Set(units[i].ActiveEnterTime)
+
+		if units[i].HasRestarts {
+			systemdUnitRestarts.WithLabelValues(units[i].Unit).Set(float64(units[i].Restarts))
+		}
+
+		if units[i].HasLastTrigger {
+			systemdTimerLastTrigger.WithLabelValues(units[i].Unit).Set(units[i].LastTrigger)
+		}
+	}
 
 	return nil
 }
 
-func gatherCPUMetrics() error {
-	cpuUtil, err := getCPUUtil()
+func gatherTimexMetrics() error {
+	timexStats, err := getTimexStats()
 	if err != nil {
 		return err
 	}
 
-	cpuTotalTime := float64(cpuUtil.User + cpuUtil.Nice + cpuUtil.System + cpuUtil.Idle + cpuUtil.IOWait + cpuUtil.IRQ + cpuUtil.SoftIRQ + cpuUtil.Steal + cpuUtil.Guest + cpuUtil.GuestNice)
-
-	idleTime := float64(cpuUtil.Idle) / cpuTotalTime
-	inUseTime := 1 - idleTime
-	userTime := float64(cpuUtil.User) / cpuTotalTime
-	systemTime := float64(cpuUtil.System) / cpuTotalTime
-	iowaitTime := float64(cpuUtil.IOWait) / cpuTotalTime
-	irqTime := float64(cpuUtil.IRQ) / cpuTotalTime
-	sirqTime := float64(cpuUtil.SoftIRQ) / cpuTotalTime
-	stealTime := float64(cpuUtil.Steal) / cpuTotalTime
-	guestTime := float64(cpuUtil.Guest) / cpuTotalTime
-	guestNiceTime := float64(cpuUtil.GuestNice) / cpuTotalTime
+	synced := 0.0
+	if timexStats.Synchronized {
+		synced = 1
+	}
 
-	cpuCores.WithLabelValues().Set(float64(getHostCPUs()))
-	cpuUtilPct.WithLabelValues().Set(inUseTime * float64(100))          //nolint
-	cpuIdlePct.WithLabelValues().Set(idleTime * float64(100))           //nolint
-	cpuUserPct.WithLabelValues().Set(userTime * float64(100))           //nolint
-	cpuSystemPct.WithLabelValues().Set(systemTime * float64(100))       //nolint
-	cpuIOWaitPct.WithLabelValues().Set(iowaitTime * float64(100))       //nolint
-	cpuIRQPct.WithLabelValues().Set(irqTime * float64(100))             //nolint
-	cpuSoftIRQPct.WithLabelValues().Set(sirqTime * float64(100))        //nolint
-	cpuStealPct.WithLabelValues().Set(stealTime * float64(100))         //nolint
-	cpuGuestPct.WithLabelValues().Set(guestTime * float64(100))         //nolint
-	cpuGuestNicePct.WithLabelValues().Set(guestNiceTime * float64(100)) //nolint
+	timexOffset.WithLabelValues().Set(timexStats.Offset)
+	timexFrequency.WithLabelValues().Set(timexStats.Frequency)
+	timexMaxError.WithLabelValues().Set(timexStats.MaxError)
+	timexEstError.WithLabelValues().Set(timexStats.EstError)
+	timexStatus.WithLabelValues().Set(timexStats.Status)
+	timexState.WithLabelValues().Set(timexStats.State)
+	timexSyncStatus.WithLabelValues().Set(synced)
+	timexUnsynchronized.WithLabelValues().Set(timexStats.UnsynchronizedSeconds)
+	timexTAIOffset.WithLabelValues().Set(timexStats.TAIOffset)
+	timexTimeConstant.WithLabelValues().Set(timexStats.TimeConstant)
+	timexTick.WithLabelValues().Set(timexStats.Tick)
+	timexTime.WithLabelValues().Set(timexStats.Time)
 
 	return nil
 }
 
-func gatherMemoryMetrics() error {
-	memory, err := getMeminfo()
+func gatherNodeInfoMetrics() error {
+	nodeInfo, err := getNodeInfo()
 	if err != nil {
 		return err
 	}
 
-	memoryTotal.WithLabelValues().Set(float64(memory.MemTotal))
-	memoryFree.WithLabelValues().Set(float64(memory.MemFree))
-	memoryCached.WithLabelValues().Set(float64(memory.Cached))
-	memoryBuffered.WithLabelValues().Set(float64(memory.Buffers))
-	memorySwapTotal.WithLabelValues().Set(float64(memory.SwapTotal))
-	memorySwapFree.WithLabelValues().Set(float64(memory.SwapFree))
+	// hostname changes, kernel upgrades and os upgrades without a reboot of v-agent
+	for _, vec := range []*prometheus.GaugeVec{nodeUnameInfo, nodeOSReleaseInfo, nodeDMIInfo} {
+		vec.Reset()
+	}
+
+	nodeUnameInfo.With(prometheus.Labels(nodeInfo.Uname)).Set(1)
+
+	osRelease := make(prometheus.Labels)
+	for _, f := range osReleaseFields {
+		osRelease[strings.ToLower(f)] = nodeInfo.OSRelease[f]
+	}
+
+	nodeOSReleaseInfo.With(osRelease).Set(1)
+
+	if nodeInfo.DMI != nil {
+		nodeDMIInfo.With(prometheus.Labels(nodeInfo.DMI)).Set(1)
+	}
+
+	// same /proc/stat btime as the cpu collector, set here too for hosts with only node_info enabled
+	cpuBootTime.WithLabelValues().Set(nodeInfo.BootTime)
+	nodeUptime.WithLabelValues().Set(nodeInfo.Uptime)
 
 	return nil
 }
 
-func gatherNICMetrics() error {
-	nicStats, err := getNICStats()
+func gatherKernelLimitsMetrics() error {
+	kernelLimits, err := getKernelLimits(procSysPath)
 	if err != nil {
 		return err
 	}
 
-	for i := range nicStats {
-		nicBytes.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Bytes))
-		nicBytesTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].BytesTX))
-		nicBytesRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].BytesRX))
-		nicPackets.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Packets))
-		nicPacketsTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].PacketsTX))
-		nicPacketsRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].PacketsRX))
-		nicErrors.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Errors))
-		nicErrorsTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].ErrorsTX))
-		nicErrorsRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].ErrorsRX))
-		nicDrop.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Drop))
-		nicDropTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].DropTX))
-		nicDropRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].DropRX))
-		nicFIFO.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FIFO))
-		nicFIFOTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FIFOTX))
-		nicFIFORX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FIFORX))
-		nicFrameRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].FrameRX))
-		nicCollsTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CollsTX))
-		nicCompressed.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].Compressed))
-		nicCompressedTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CompressedTX))
-		nicCompressedRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CompressedRX))
-		nicCarrierTX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].CarrierTX))
-		nicMulticastRX.WithLabelValues(nicStats[i].Interface).Set(float64(nicStats[i].MulticastRX))
+	kernelFileAllocated.WithLabelValues().Set(kernelLimits.FileAllocated)
+	kernelFileMax.WithLabelValues().Set(kernelLimits.FileMax)
+	kernelInodeTotal.WithLabelValues().Set(kernelLimits.InodeTotal)
+	kernelInodeFree.WithLabelValues().Set(kernelLimits.InodeFree)
+	kernelEntropyAvail.WithLabelValues().Set(kernelLimits.EntropyAvail)
+	kernelEntropyPoolSize.WithLabelValues().Set(kernelLimits.EntropyPoolSize)
+	kernelPIDMax.WithLabelValues().Set(kernelLimits.PIDMax)
+	kernelThreadsMax.WithLabelValues().Set(kernelLimits.ThreadsMax)
+	kernelThreads.WithLabelValues().Set(kernelLimits.Threads)
+	kernelInotifyMaxWatches.WithLabelValues().Set(kernelLimits.InotifyMaxUserWatches)
+	kernelInotifyMaxInstances.WithLabelValues().Set(kernelLimits.InotifyMaxUserInstances)
+
+	if !config.KernelLimitsInotifyEnabled() {
+		return nil
 	}
 
-	return nil
-}
-
-func gatherDiskMetrics() error {
-	diskStats, err := getDiskStatsUtil()
+	inotifyUsage, err := getInotifyUsage(procPath)
 	if err != nil {
 		return err
 	}
 
-	for i := range diskStats {
-		diskStatsReads.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].Reads))
-		diskStatsReadsMerged.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].ReadsMerged))
-		diskStatsSectorsRead.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].SectorsRead))
-		diskStatsMillisecondsReading.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].MillisecondsReading))
-		diskStatsWritesCompleted.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].WritesCompleted))
-		diskStatsWritesMerged.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].WritesMerged))
-		diskStatsSectorsWritten.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].SectorsWritten))
-		diskStatsMillisecondsWriting.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].MillisecondsWriting))
-		diskStatsIOsInProgress.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].IOsInProgress))
-		diskStatsMillisecondsInIOs.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].MillisecondsInIOs))
-		diskStatsWeightedIOsInMS.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].WeightedIOsInMS))
-		diskStatsDiscards.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].Discards))
-		diskStatsDiscardsMerged.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].DiscardsMerged))
-		diskStatsSectorsDiscarded.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].SectorsDiscarded))
-		diskStatsMillisecondsDiscarding.WithLabelValues(diskStats[i].Device).Set(float64(diskStats[i].MillisecondsDiscarding))
+	// users come and go
+	kernelInotifyWatches.Reset()
+	kernelInotifyInstances.Reset()
+
+	for i := range inotifyUsage {
+		kernelInotifyWatches.WithLabelValues(inotifyUsage[i].UID).Set(float64(inotifyUsage[i].Watches))
+		kernelInotifyInstances.WithLabelValues(inotifyUsage[i].UID).Set(float64(inotifyUsage[i].Instances))
 	}
 
 	return nil
 }
 
-func gatherFilesystemMetrics() error {
-	fsStats, err := getFilesystemUtil()
+func gatherNUMAMetrics() error {
+	numaStats, err := getNUMAStats(sysDevicesNodePath)
 	if err != nil {
 		return err
 	}
 
-	for i := range fsStats {
-		fsInodes.WithLabelValues(fsStats[i].Device, fsStats[i].Mount).Set(float64(fsStats[i].Inodes))
-		fsInodesUsed.WithLabelValues(fsStats[i].Device, fsStats[i].Mount).Set(float64(fsStats[i].InodesUsed))
-		fsInodesUtil.WithLabelValues(fsStats[i].Device, fsStats[i].Mount).Set(fsStats[i].InodesUtil)
-		fsBytes.WithLabelValues(fsStats[i].Device, fsStats[i].Mount).Set(float64(fsStats[i].BytesTotal))
-		fsBytesUsed.WithLabelValues(fsStats[i].Device, fsStats[i].Mount).Set(float64(fsStats[i].BytesUsed))
-		fsBytesUtil.WithLabelValues(fsStats[i].Device, fsStats[i].Mount).Set(fsStats[i].BytesUtil)
+	// nodes come and go with memory hotplug
+	for _, vec := range []*prometheus.GaugeVec{numaHugePagesTotal, numaHugePagesFree, numaHugePagesSurplus} {
+		vec.Reset()
 	}
 
-	return nil
-}
-
-func gatherKubernetesMetrics() error {
-	log := zap.L().Sugar()
+	resetDynamicGaugeVecs("v_numa_")
+	resetDynamicGaugeVecs("v_numastat_")
 
-	if err := DoKubeAPIServerHealthCheck(); err != nil {
-		log.Error(err)
+	for i := range numaStats {
+		ns := numaStats[i]
 
-		kubeAPIServerHealthz.WithLabelValues().Set(float64(1))
-	} else {
-		kubeAPIServerHealthz.WithLabelValues().Set(float64(0))
-	}
+		// hugepage counts (HugePages_Total, etc) are covered per page size by v_numa_hugepages*
+		for field, v := range ns.Meminfo {
+			if !v.Bytes {
+				continue
+			}
 
-	if err := ScrapeKubeAPIServerMetrics(); err != nil {
-		return err
-	}
+			name := fmt.Sprintf("v_numa_%s_bytes", sanitizeMetricName(field))
 
-	return nil
-}
+			getDynamicGaugeVec(name, fmt.Sprintf("node*/meminfo: %s", field), []string{"node"}).WithLabelValues(ns.Node).Set(float64(v.Value))
+		}
 
-func gatherKonnectivityMetrics() error {
-	log := zap.L().Sugar()
+		for stat, v := range ns.NUMAStat {
+			name := fmt.Sprintf("v_numastat_%s", sanitizeMetricName(stat))
 
-	if err := DoKonnectivityHealthCheck(); err != nil {
-		log.Error(err)
+			getDynamicGaugeVec(name, fmt.Sprintf("node*/numastat: %s", stat), []string{"node"}).WithLabelValues(ns.Node).Set(float64(v))
+		}
 
-		konnectivityHealthz.WithLabelValues().Set(float64(1))
-	} else {
-		konnectivityHealthz.WithLabelValues().Set(float64(0))
-	}
+		for _, pool := range ns.HugePages {
+			pageSize := strconv.FormatUint(pool.PageSize, 10)
 
-	if err := ScrapeKonnectivityMetrics(); err != nil {
-		return err
+			numaHugePagesTotal.WithLabelValues(ns.Node, pageSize).Set(float64(pool.Total))
+			numaHugePagesFree.WithLabelValues(ns.Node, pageSize).Set(float64(pool.Free))
+			numaHugePagesSurplus.WithLabelValues(ns.Node, pageSize).Set(float64(pool.Surplus))
+		}
 	}
 
 	return nil
 }
 
-func gatherEtcdMetrics() error {
+func gatherKmsgMetrics() error {
 	log := zap.L().Sugar()
 
-	if err := DoEtcdHealthCheck(); err != nil {
-		log.Error(err)
+	if kmsg == nil {
+		w, err := newKmsgWatcher(devKmsgPath, config.GetKmsgStateFile(), config.GetKmsgPatterns())
+		if err != nil {
+			return err
+		}
 
-		etcdServerHealth.WithLabelValues().Set(float64(1))
-	} else {
-		etcdServerHealth.WithLabelValues().Set(float64(0))
+		kmsg = w
 	}
 
-	if err := ScrapeEtcdMetrics(); err != nil {
+	if err := kmsg.poll(); err != nil {
+		// containers without /dev/kmsg, kernel.dmesg_restrict without CAP_SYSLOG
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, os.ErrPermission) {
+			log.Warnf("kmsg: %s: %s, skipping", devKmsgPath, err)
+
+			return nil
+		}
+
 		return err
 	}
 
+	if err := kmsg.saveState(); err != nil {
+		log.Warnf("kmsg: %s: %s", kmsg.stateFile, err)
+	}
+
+	for _, m := range kmsg.stats() {
+		kmsgMatches.WithLabelValues(m.Pattern, m.Match).Set(float64(m.Count))
+	}
+
+	kmsgRecords.WithLabelValues().Set(float64(kmsg.records))
+	kmsgDropped.WithLabelValues().Set(float64(kmsg.dropped))
+
 	return nil
 }
 
-func gatherNginxVTSMetrics() error {
-	log := zap.L().Sugar()
+func gatherEDACMetrics() error {
+	edacStats, err := getEDACStats(sysEDACMCPath)
+	if err != nil {
+		return err
+	}
 
-	if err := DoNginxVTSHealthCheck(); err != nil {
-		log.Error(err)
+	for i := range edacStats {
+		es := edacStats[i]
 
-		nginxVtsHealthy.WithLabelValues().Set(float64(1))
-	} else {
-		nginxVtsHealthy.WithLabelValues().Set(float64(0))
-	}
+		edacMCCorrectable.WithLabelValues(es.Controller, es.Name).Set(es.CECount)
+		edacMCUncorrectable.WithLabelValues(es.Controller, es.Name).Set(es.UECount)
+		edacMCCorrectableNoInfo.WithLabelValues(es.Controller, es.Name).Set(es.CENoInfoCount)
+		edacMCUncorrectableNoInfo.WithLabelValues(es.Controller, es.Name).Set(es.UENoInfoCount)
 
-	if err := ScrapeNginxVTSMetrics(); err != nil {
-		return err
+		for _, csrow := range es.CSRows {
+			edacCSRowCorrectable.WithLabelValues(es.Controller, csrow.ID).Set(csrow.CECount)
+			edacCSRowUncorrectable.WithLabelValues(es.Controller, csrow.ID).Set(csrow.UECount)
+		}
+
+		for _, dimm := range es.DIMMs {
+			edacDIMMCorrectable.WithLabelValues(es.Controller, dimm.ID, dimm.Label).Set(dimm.CECount)
+			edacDIMMUncorrectable.WithLabelValues(es.Controller, dimm.ID, dimm.Label).Set(dimm.UECount)
+		}
 	}
 
 	return nil
 }
 
-func gatherVCDNAgentMetrics() error {
-	log := zap.L().Sugar()
+func gatherNFSMetrics() error {
+	clientStats, err := getNFSClientStats(procNetRPCNFSPath)
+	if err != nil {
+		return err
+	}
 
-	if err := DoVCDNAgentHealthCheck(); err != nil {
-		log.Error(err)
+	mountStats, err := getNFSMountStats(procMountstatsPath)
+	if err != nil {
+		return err
+	}
 
-		vcdnAgentHealth.WithLabelValues().Set(float64(1))
-	} else {
-		vcdnAgentHealth.WithLabelValues().Set(float64(0))
+	// nfs pv mounts come and go with pods, don't keep reporting unmounted ones
+	for _, vec := range []*prometheus.GaugeVec{
+		nfsClientRequests, nfsMountAge, nfsMountReadBytes, nfsMountWriteBytes,
+		nfsMountTransportSends, nfsMountTransportReceives, nfsMountTransportBadXIDs,
+		nfsMountOperations, nfsMountOperationRetransmissions, nfsMountOperationTimeouts, nfsMountOperationSentBytes,
+		nfsMountOperationReceivedBytes, nfsMountOperationQueueTime, nfsMountOperationRTT, nfsMountOperationExecuteTime,
+		nfsMountOperationErrors,
+	} {
+		vec.Reset()
 	}
 
-	if err := ScrapeVCDNAgentMetrics(); err != nil {
-		return err
+	// nil when the nfs client module isn't loaded
+	if clientStats != nil {
+		nfsClientPackets.WithLabelValues("udp").Set(clientStats.UDPPackets)
+		nfsClientPackets.WithLabelValues("tcp").Set(clientStats.TCPPackets)
+		nfsClientTCPConnections.WithLabelValues().Set(clientStats.TCPConnections)
+		nfsClientRPCCalls.WithLabelValues().Set(clientStats.RPCCalls)
+		nfsClientRPCRetransmits.WithLabelValues().Set(clientStats.RPCRetransmits)
+		nfsClientRPCAuthRefreshes.WithLabelValues().Set(clientStats.RPCAuthRefreshs)
+
+		for _, req := range clientStats.Requests {
+			nfsClientRequests.WithLabelValues(req.Version, req.Op).Set(req.Count)
+		}
 	}
 
-	return nil
-}
+	for i := range mountStats {
+		ms := mountStats[i]
 
-func gatherHAProxyMetrics() error {
-	log := zap.L().Sugar()
+		nfsMountAge.WithLabelValues(ms.Export, ms.Mountpoint, ms.Type).Set(ms.Age)
 
-	if err := DoHAProxyHealthCheck(); err != nil {
-		log.Error(err)
+		nfsMountReadBytes.WithLabelValues(ms.Export, ms.Mountpoint, "normal").Set(ms.NormalReadBytes)
+		nfsMountReadBytes.WithLabelValues(ms.Export, ms.Mountpoint, "direct").Set(ms.DirectReadBytes)
+		nfsMountReadBytes.WithLabelValues(ms.Export, ms.Mountpoint, "server").Set(ms.ServerReadBytes)
+		nfsMountWriteBytes.WithLabelValues(ms.Export, ms.Mountpoint, "normal").Set(ms.NormalWriteBytes)
+		nfsMountWriteBytes.WithLabelValues(ms.Export, ms.Mountpoint, "direct").Set(ms.DirectWriteBytes)
+		nfsMountWriteBytes.WithLabelValues(ms.Export, ms.Mountpoint, "server").Set(ms.ServerWriteBytes)
 
-		haproxyHealthy.WithLabelValues().Set(float64(1))
-	} else {
-		haproxyHealthy.WithLabelValues().Set(float64(0))
-	}
+		if xprt := ms.Transport; xprt != nil {
+			nfsMountTransportSends.WithLabelValues(ms.Export, ms.Mountpoint, xprt.Protocol).Set(xprt.Sends)
+			nfsMountTransportReceives.WithLabelValues(ms.Export, ms.Mountpoint, xprt.Protocol).Set(xprt.Receives)
+			nfsMountTransportBadXIDs.WithLabelValues(ms.Export, ms.Mountpoint, xprt.Protocol).Set(xprt.BadXIDs)
+		}
 
-	if err := ScrapeHAProxyMetrics(); err != nil {
-		return err
+		for _, op := range ms.Ops {
+			// nfs4 lists every op the client knows about, most are never used
+			if op.Operations == 0 {
+				continue
+			}
+
+			nfsMountOperations.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.Operations)
+			nfsMountOperationRetransmissions.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.Retransmissions)
+			nfsMountOperationTimeouts.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.MajorTimeouts)
+			nfsMountOperationSentBytes.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.BytesSent)
+			nfsMountOperationReceivedBytes.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.BytesReceived)
+			nfsMountOperationQueueTime.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.QueueSeconds)
+			nfsMountOperationRTT.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.RTTSeconds)
+			nfsMountOperationExecuteTime.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.ExecuteSeconds)
+			nfsMountOperationErrors.WithLabelValues(ms.Export, ms.Mountpoint, op.Op).Set(op.Errors)
+		}
 	}
 
 	return nil
 }
 
-func gatherCephMetrics() error {
-	if err := ScrapeCephMetrics(); err != nil {
-		return err
+// getDynamicGaugeVec returns the gauge for name, registering it the first time it's seen
+//
+// used for sources where the set of fields is only known once read and varies by kernel (/proc/meminfo, etc)
+func getDynamicGaugeVec(name, help string, labels []string) *prometheus.GaugeVec {
+	if g, ok := dynamicGauges[name]; ok {
+		return g
 	}
 
-	return nil
-}
+	g := promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: name,
+			Help: help,
+		},
+		labels,
+	)
 
-func gatherVDNSMetrics() error {
-	log := zap.L().Sugar()
+	dynamicGauges[name] = g
 
-	if err := DoVDNSHealthCheck(); err != nil {
-		log.Error(err)
+	return g
+}
 
-		vdnsHealthy.WithLabelValues().Set(float64(1))
-	} else {
-		vdnsHealthy.WithLabelValues().Set(float64(0))
+// resetDynamicGaugeVecs resets every gauge registered by getDynamicGaugeVec with a name starting with prefix, for
+// collectors whose labels (interfaces, numa nodes) can disappear between gathers
+func resetDynamicGaugeVecs(prefix string) {
+	for name, g := range dynamicGauges {
+		if strings.HasPrefix(name, prefix) {
+			g.Reset()
+		}
 	}
+}
 
-	if err := ScrapeVDNSMetrics(); err != nil {
-		return err
-	}
+// sanitizeMetricName lowercases s and replaces anything not valid in a metric name with _
+//
+// Active(anon) becomes active_anon
+func sanitizeMetricName(s string) string {
+	var b strings.Builder
 
-	return nil
-}
+	underscore := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
 
-func gatherScrapeablePodsMetrics() error {
-	if err := ScrapeKubernetesPods(); err != nil {
-		return err
+			continue
+		}
+
+		if !underscore {
+			b.WriteRune('_')
+			underscore = true
+		}
 	}
 
-	return nil
+	return strings.Trim(b.String(), "_")
 }
 
-func gatherSMARTmetrics() error {
-	ResetSMARTMetrics()
+func gatherPSIMetrics() error {
+	log := zap.L().Sugar()
 
-	if err := ScrapeSMARTMetrics(); err != nil {
+	psi, err := getPSI()
+	if err != nil {
 		return err
 	}
 
-	return nil
-}
+	for i := range psi {
+		psiAvg10.WithLabelValues(psi[i].Resource, psi[i].Type).Set(psi[i].Avg10)
+		psiAvg60.WithLabelValues(psi[i].Resource, psi[i].Type).Set(psi[i].Avg60)
+		psiAvg300.WithLabelValues(psi[i].Resource, psi[i].Type).Set(psi[i].Avg300)
+		psiStallTotal.WithLabelValues(psi[i].Resource, psi[i].Type).Set(float64(psi[i].Total) / float64(time.Second/time.Microsecond))
+	}
 
-func gatherDCGMmetrics() error {
-	if err := ScrapeDCGMMetrics(); err != nil {
-		return err
+	// cgroups come and go, don't keep series for removed ones
+	psiCgroupAvg10.Reset()
+	psiCgroupAvg60.Reset()
+	psiCgroupAvg300.Reset()
+	psiCgroupStallTotal.Reset()
+
+	for _, cgroup := range config.GetPSICgroups() {
+		cgroupPSI, err := getCgroupPSI(cgroup)
+		if err != nil {
+			log.Warnf("psi: cgroup %q: %s", cgroup, err)
+
+			continue
+		}
+
+		for i := range cgroupPSI {
+			psiCgroupAvg10.WithLabelValues(cgroup, cgroupPSI[i].Resource, cgroupPSI[i].Type).Set(cgroupPSI[i].Avg10)
+			psiCgroupAvg60.WithLabelValues(cgroup, cgroupPSI[i].Resource, cgroupPSI[i].Type).Set(cgroupPSI[i].Avg60)
+			psiCgroupAvg300.WithLabelValues(cgroup, cgroupPSI[i].Resource, cgroupPSI[i].Type).Set(cgroupPSI[i].Avg300)
+			psiCgroupStallTotal.WithLabelValues(cgroup, cgroupPSI[i].Resource, cgroupPSI[i].Type).Set(float64(cgroupPSI[i].Total) / float64(time.Second/time.Microsecond))
+		}
 	}
 
 	return nil
@@ -0,0 +1,65 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func TestIncludeExcludeFilter(t *testing.T) {
+	f, err := newIncludeExcludeFilter("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !f.match("eth0") {
+		t.Error("expect empty include/exclude to match everything")
+	}
+
+	f, err = newIncludeExcludeFilter("^(eth|enp)", "^eth1$")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expect := range map[string]bool{
+		"eth0":      true,
+		"enp1s0":    true,
+		"eth1":      false,
+		"veth12345": false,
+	} {
+		if got := f.match(name); got != expect {
+			t.Errorf("match(%q) = %t, expect %t", name, got, expect)
+		}
+	}
+
+	if _, err := newIncludeExcludeFilter("(", ""); err == nil {
+		t.Error("expect invalid regex to return an error")
+	}
+}
+
+func TestResetDynamicGaugeVecs(t *testing.T) {
+	eth := getDynamicGaugeVec("v_ethtool_stat_test_reset", "test", []string{"nic"})
+	numa := getDynamicGaugeVec("v_numastat_test_reset", "test", []string{"node"})
+
+	eth.WithLabelValues("eth0").Set(1)
+	numa.WithLabelValues("0").Set(1)
+
+	resetDynamicGaugeVecs("v_ethtool_stat_")
+
+	if n := countSeries(eth); n != 0 {
+		t.Errorf("expected v_ethtool_stat_ to be reset, got %d series", n)
+	}
+
+	if n := countSeries(numa); n != 1 {
+		t.Errorf("expected v_numastat_ to be kept, got %d series", n)
+	}
+}
+
+func countSeries(g *prometheus.GaugeVec) int {
+	ch := make(chan prometheus.Metric, 16) //nolint
+	g.Collect(ch)
+	close(ch)
+
+	return len(ch)
+}
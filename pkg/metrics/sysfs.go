@@ -0,0 +1,48 @@
+// Package metrics metrics collection
+package metrics
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// readSysString reads a single value sysfs/procfs file (attribute, sysctl)
+func readSysString(path string) (string, error) {
+	data, err := os.ReadFile(path) //nolint
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// readSysFloat reads a single number sysfs/procfs file
+func readSysFloat(path string) (float64, error) {
+	s, err := readSysString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseFloat(s, 64)
+}
+
+// readSysStringOr is readSysString with def for a missing or unreadable attribute
+func readSysStringOr(path, def string) string {
+	s, err := readSysString(path)
+	if err != nil {
+		return def
+	}
+
+	return s
+}
+
+// readSysFloatOr is readSysFloat with def for a missing, unreadable or non numeric attribute
+func readSysFloatOr(path string, def float64) float64 {
+	v, err := readSysFloat(path)
+	if err != nil {
+		return def
+	}
+
+	return v
+}